@@ -1,10 +1,31 @@
 package abnfp
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+)
 
+// ErrOutOfRange is returned when the offset to parse from is out of the data.
+var ErrOutOfRange = errors.New("abnfp: offset out of range")
+
+// ParseResult is the result of ParseAt.
 type ParseResult struct {
+	// Parsed is the found data, and Remaining is the data after it.
 	Parsed    []byte
 	Remaining []byte
+	// Span is the offsets of Parsed in the data passed to ParseAt.
+	Span
+	// Rule is the name of the rule if the Finder is a RuleFinder.
+	Rule string
+	// Tree is the tree of the rules found in Parsed, like ParseTree returns.
+	// It is nil unless the parse is WithTree.
+	Tree *Node
+	// Err wraps ErrNotFound if the syntax is not found, and also
+	// ErrUnboundProseVal if the Finder has the prose-vals not bound. It wraps
+	// ErrAborted if the parse is aborted, or ErrOutOfRange if the start is out
+	// of the data.
+	Err error
 }
 
 type VariableFinder interface {
@@ -18,20 +39,83 @@ type Finder interface {
 	Copy() Finder
 }
 
-var Debug bool = false
+// parentFinder is implemented by the Finders which have child Finders.
+// foundChildren returns the child Finders which found the syntax at the last
+// Find or Recalculate, and where they found it. end is the end found by the parent.
+type parentFinder interface {
+	foundChildren(end int) []foundChild
+}
 
-func DebugLog(format string, params ...any) {
-	if Debug {
-		fmt.Printf(format, params...)
+type foundChild struct {
+	finder Finder
+	start  int
+	end    int
+}
+
+// Parse finds the syntax from the beginning of data, and returns the found
+// data and the rest.
+// If the parse is aborted by WithContext or WithMaxSteps, the syntax is not
+// found. Use ParseContext to know why it is not found.
+func Parse(data []byte, finder Finder, opts ...Option) (parsed []byte, remaining []byte) {
+	parsed, remaining, _ = parse(data, finder, opts)
+	return parsed, remaining
+}
+
+// ParseAt finds the syntax from the offset start of data, and returns the
+// result with the offsets in data, so that the next syntax can be found from
+// the End of the result.
+// If the syntax is not found, Parsed is empty and End is start.
+// start must be from 0 to the length of data, otherwise Err wraps
+// ErrOutOfRange and Remaining is empty.
+func ParseAt(data []byte, start int, finder Finder, opts ...Option) ParseResult {
+	result := ParseResult{Parsed: []byte{}, Remaining: []byte{}, Span: Span{Start: start, End: start}}
+	if ruleFinder, ok := finder.(*RuleFinder); ok {
+		result.Rule = ruleFinder.name
+	}
+	if start < 0 || start > len(data) {
+		result.Err = fmt.Errorf("%w: start %v, length %v", ErrOutOfRange, start, len(data))
+		return result
+	}
+	p := newParser(data, opts)
+	finder = p.prepare(finder)
+	result.Remaining = data[start:]
+	found, end, err := p.find(finder, data[start:])
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if !found {
+		result.Err = notFound(finder, opts)
+		return result
+	}
+	result.Parsed = data[start : start+end]
+	result.Remaining = data[start+end:]
+	result.End = start + end
+	if p != nil && p.tree {
+		result.Tree = newTree(finder, start, start+end)
 	}
+	return result
 }
 
-func Parse(data []byte, finder Finder) (parsed []byte, remaining []byte) {
-	found, end := finder.Find(data)
+// ParseContext is Parse which is aborted when ctx is done.
+// It returns the error which wraps ErrNotFound if the syntax is not found, and
+// also ErrUnboundProseVal if finder has the prose-vals not bound, or the error
+// which wraps ErrAborted if the parse is aborted by ctx or WithMaxSteps.
+func ParseContext(ctx context.Context, data []byte, finder Finder, opts ...Option) (parsed []byte, remaining []byte, err error) {
+	return parse(data, finder, append([]Option{WithContext(ctx)}, opts...))
+}
+
+func parse(data []byte, finder Finder, opts []Option) (parsed []byte, remaining []byte, err error) {
+	p := newParser(data, opts)
+	finder = p.prepare(finder)
+	found, end, err := p.find(finder, data)
+	if err != nil {
+		return []byte{}, data, err
+	}
 	if !found {
-		return []byte{}, data
+		return []byte{}, data, notFound(finder, opts)
 	}
-	return data[:end], data[end:]
+	return data[:end], data[end:], nil
 }
 
 type ByteFinder struct {
@@ -112,6 +196,146 @@ func NewCrLfFinder() *CrLfFinder {
 	return &CrLfFinder{}
 }
 
+// RFC5234 - 2.3. Terminal Values
+// ABNF permits the specification of literal text strings directly,
+// enclosed in quotation marks.  Hence:
+//
+//  command = "command string"
+//
+// NOTE:
+// ABNF strings are case insensitive and the character set for these
+// strings is US-ASCII.
+//
+// So CaseInsensitiveBytesFinder finds the letters of its target regardless of
+// their case, e.g. "abc" finds "abc", "Abc", "aBc", "abC", "ABc", "aBC", "AbC"
+// and "ABC". Use BytesFinder for the case sensitive strings.
+
+type CaseInsensitiveBytesFinder struct {
+	target []byte
+}
+
+func (finder CaseInsensitiveBytesFinder) Find(data []byte) (found bool, end int) {
+	if len(finder.target) == 0 {
+		return
+	}
+	if len(data) < len(finder.target) {
+		return
+	}
+	for i, t := range finder.target {
+		if toLowerByte(data[i]) != toLowerByte(t) {
+			return false, 0
+		}
+	}
+	return true, len(finder.target)
+}
+
+func (finder CaseInsensitiveBytesFinder) Copy() Finder {
+	targetCopy := append([]byte{}, finder.target...)
+	return CaseInsensitiveBytesFinder{target: targetCopy}
+}
+
+// caseSensitiveFinder returns the Finder which finds the same syntax with the
+// case sensitive Finders, like ( %x61 / %x41 ) %x62.
+func (finder CaseInsensitiveBytesFinder) caseSensitiveFinder() Finder {
+	childFinders := []Finder{}
+	for _, t := range finder.target {
+		if toLowerByte(t) == toUpperByte(t) {
+			childFinders = append(childFinders, NewByteFinder(t))
+			continue
+		}
+		childFinders = append(childFinders, NewAlternativesFinder([]Finder{
+			NewByteFinder(toLowerByte(t)),
+			NewByteFinder(toUpperByte(t)),
+		}))
+	}
+	return NewConcatenationFinder(childFinders)
+}
+
+func NewCaseInsensitiveBytesFinder(target []byte) *CaseInsensitiveBytesFinder {
+	targetCopy := append([]byte{}, target...)
+	return &CaseInsensitiveBytesFinder{target: targetCopy}
+}
+
+// toLowerByte returns the lower case of b if it is an US-ASCII upper case letter.
+func toLowerByte(b byte) byte {
+	if 'A' <= b && b <= 'Z' {
+		return b + ('a' - 'A')
+	}
+	return b
+}
+
+// toUpperByte returns the upper case of b if it is an US-ASCII lower case letter.
+func toUpperByte(b byte) byte {
+	if 'a' <= b && b <= 'z' {
+		return b - ('a' - 'A')
+	}
+	return b
+}
+
+// RFC5234 - 2.2. Rule Form
+// A rule is defined by the following sequence:
+//
+//  name =  elements crlf
+//
+// where <name> is the name of the rule, <elements> is one or more rule
+// names or terminal specifications, and <crlf> is the end-of-line
+// indicator (carriage return followed by line feed).
+//
+// NOTE
+// RuleFinder creates the Finder of its elements by calling newFinder when
+// Find is called, not when RuleFinder is created. So newFinder can refer
+// to the rule itself, directly or indirectly, like
+//
+//  comment = "(" *( ctext / comment ) ")"
+//
+
+type RuleFinder struct {
+	name      string
+	newFinder func() Finder
+	finder    Finder
+	parser    *parser
+}
+
+func (finder *RuleFinder) Find(data []byte) (found bool, end int) {
+	finder.finder = finder.newFinder()
+	setParser(finder.finder, finder.parser)
+	finder.parser.enterRule(finder.name, data)
+	found, end = finder.finder.Find(data)
+	finder.parser.exitRule(finder.name, data, found, end)
+	return
+}
+
+func (finder RuleFinder) Copy() Finder {
+	return &RuleFinder{name: finder.name, newFinder: finder.newFinder, parser: finder.parser}
+}
+
+func (finder *RuleFinder) Recalculate(data []byte) (found bool, end int) {
+	finder.parser.pushRule(finder.name)
+	if variableFinder, ok := finder.finder.(VariableFinder); ok {
+		found, end = variableFinder.Recalculate(data)
+	}
+	finder.parser.recalculateRule(finder.name, data, found, end)
+	return
+}
+
+func (finder *RuleFinder) foundChildren(end int) []foundChild {
+	return []foundChild{{finder: finder.finder, start: 0, end: end}}
+}
+
+// setParser doesn't set p to the Finder of the rule, because it is created
+// at each Find.
+func (finder *RuleFinder) setParser(p *parser) {
+	finder.parser = p
+}
+
+func (finder *RuleFinder) Name() string {
+	return finder.name
+}
+
+func NewRuleFinder(name string, newFinder func() Finder) *RuleFinder {
+	return &RuleFinder{name: name, newFinder: newFinder}
+}
+
 // RFC5234 - 3.1. Concatenation: Rule1 Rule2
 // A rule can define a simple, ordered string of values (i.e., a
 // concatenation of contiguous characters) by listing a sequence of rule
@@ -126,32 +350,31 @@ func NewCrLfFinder() *CrLfFinder {
 type ConcatenationFinder struct {
 	childFinders []Finder
 	childEnds    []int
+	parser       *parser
 }
 
 func (finder *ConcatenationFinder) Find(data []byte) (found bool, end int) {
-	DebugLog("Concatenation.Find() start.\n")
 	finder.childEnds = []int{}
+	if len(finder.childFinders) == 0 {
+		// NOTE
+		// Recalculate() finds the syntax of the remaining finders with a new
+		// ConcatenationFinder. When the last finder is recalculated, it has no finders.
+		return true, 0
+	}
 	remaining := data
 	for i := 0; i < len(finder.childFinders); i++ {
-		DebugLog("Concatenation.Find() execute childFinders[%v]\n", i)
 		childFinder := finder.childFinders[i]
 		childFound, childEnd := childFinder.Find(remaining)
 		if !childFound {
-			DebugLog("Concatenation.Find() execute childFinders[%v] failed. Execute Recalculate().\n", i)
-			found, end = finder.Recalculate(data)
-			if found {
-				DebugLog("Concatenation.Recalculate() success. Final end is %v. childEnds is  %v.\n", end, finder.childEnds)
-			}
-			return
+			finder.parser.backtrack(data)
+			return finder.Recalculate(data)
 		}
-		DebugLog("ChildFinders[%v] found the syntax. input is %s, end is %v.\n", i, remaining, childEnd)
 		remaining = remaining[childEnd:]
 		if i != 0 {
 			childEnd += finder.childEnds[i-1]
 		}
 		finder.childEnds = append(finder.childEnds, childEnd)
 	}
-	DebugLog("Concatenation.Find() finish. Final childEnds is %v.\n", finder.childEnds)
 	return true, finder.childEnds[len(finder.childEnds)-1]
 }
 
@@ -160,15 +383,13 @@ func (finder ConcatenationFinder) Copy() Finder {
 	for _, childFinder := range finder.childFinders {
 		childFindersCopy = append(childFindersCopy, childFinder.Copy())
 	}
-	return &ConcatenationFinder{childFinders: childFindersCopy, childEnds: []int{}}
+	return &ConcatenationFinder{childFinders: childFindersCopy, childEnds: []int{}, parser: finder.parser}
 }
 
 func (finder *ConcatenationFinder) Recalculate(data []byte) (found bool, end int) {
-	DebugLog("Concatenation.Recalculate() start.\n")
 	var remaining []byte
 	childEnds := finder.childEnds
 	for i := len(finder.childEnds) - 1; i >= 0; i-- {
-		DebugLog("Recalculate childFinders[%v].\n", i)
 		// restore remaining and childEnds
 		if i == 0 {
 			remaining = data
@@ -177,32 +398,27 @@ func (finder *ConcatenationFinder) Recalculate(data []byte) (found bool, end int
 			remaining = data[finder.childEnds[i-1]:]
 			childEnds = finder.childEnds[:i]
 		}
-		DebugLog("Restored remaining: %s. Restored childEnds: %v.\n", remaining, childEnds)
 
 		childFinder := finder.childFinders[i]
 		switch cf := childFinder.(type) {
 		case VariableFinder:
 			otherFound, otherEnd := cf.Recalculate(remaining)
 			if !otherFound {
-				DebugLog("childFinders[%v] could not find another data.\n", i)
 				continue
 			}
-			DebugLog("childFinders[%v] found another data. Input is %s, end is %v.\n", i, remaining, otherEnd)
 			if i != 0 {
 				otherEnd += childEnds[i-1]
 			}
 
 			remainingChildFinders := finder.childFinders[i+1:]
-			DebugLog("Check that the remaining finders can find each syntax. Number of remaining finders is %v\n", len(remainingChildFinders))
 			remainingConcatenationFinder := NewConcatenationFinder(remainingChildFinders)
-			remainingFound, j := remainingConcatenationFinder.Find(data[otherEnd:])
+			remainingConcatenationFinder.parser = finder.parser
+			remainingFound, _ := remainingConcatenationFinder.Find(data[otherEnd:])
 			if !remainingFound {
-				DebugLog("The remainingFinders[%v] could not find the syntax. Recalculate childFinders[%v] one more time.\n", j, i)
 				i++ // The current cf has other choice. So recalculate one more time.
 				continue
 			}
 
-			DebugLog("All remainingFinders found the syntax. Recalculation success.\n")
 			// Merge childEnds
 			childEnds = append(childEnds, otherEnd)
 			for _, remainingEnd := range remainingConcatenationFinder.childEnds {
@@ -222,6 +438,23 @@ func (finder *ConcatenationFinder) Recalculate(data []byte) (found bool, end int
 	return false, 0
 }
 
+func (finder *ConcatenationFinder) foundChildren(end int) []foundChild {
+	children := []foundChild{}
+	start := 0
+	for i, childEnd := range finder.childEnds {
+		children = append(children, foundChild{finder: finder.childFinders[i], start: start, end: childEnd})
+		start = childEnd
+	}
+	return children
+}
+
+func (finder *ConcatenationFinder) setParser(p *parser) {
+	finder.parser = p
+	for _, childFinder := range finder.childFinders {
+		setParser(childFinder, p)
+	}
+}
+
 func NewConcatenationFinder(finders []Finder) *ConcatenationFinder {
 	findersCopy := []Finder{}
 	for _, finder := range finders {
@@ -241,10 +474,12 @@ func NewConcatenationFinder(finders []Finder) *ConcatenationFinder {
 type AlternativesFinder struct {
 	childFinders     []Finder
 	remainingFinders []Finder
+	foundFinder      Finder
 }
 
 func (finder *AlternativesFinder) Find(data []byte) (found bool, end int) {
 	finder.remainingFinders = finder.childFinders
+	finder.foundFinder = nil
 	return finder.Recalculate(data)
 }
 
@@ -257,10 +492,21 @@ func (finder AlternativesFinder) Copy() Finder {
 }
 
 func (finder *AlternativesFinder) Recalculate(data []byte) (found bool, end int) {
+	// NOTE
+	// The found alternative might find other data. e.g. ( *a ) / b
+	// Try it before the remaining alternatives.
+	if foundFinder, ok := finder.foundFinder.(VariableFinder); ok {
+		found, end = foundFinder.Recalculate(data)
+		if found {
+			return
+		}
+	}
+	finder.foundFinder = nil
 	for _, childFinder := range finder.remainingFinders {
 		finder.remainingFinders = finder.remainingFinders[1:]
 		childFound, childEnd := childFinder.Find(data)
 		if childFound {
+			finder.foundFinder = childFinder
 			found = childFound
 			end = childEnd
 			break
@@ -269,6 +515,19 @@ func (finder *AlternativesFinder) Recalculate(data []byte) (found bool, end int)
 	return
 }
 
+func (finder *AlternativesFinder) foundChildren(end int) []foundChild {
+	if finder.foundFinder == nil {
+		return []foundChild{}
+	}
+	return []foundChild{{finder: finder.foundFinder, start: 0, end: end}}
+}
+
+func (finder *AlternativesFinder) setParser(p *parser) {
+	for _, childFinder := range finder.childFinders {
+		setParser(childFinder, p)
+	}
+}
+
 func NewAlternativesFinder(finders []Finder) *AlternativesFinder {
 	findersCopy := []Finder{}
 	for _, finder := range finders {
@@ -277,6 +536,95 @@ func NewAlternativesFinder(finders []Finder) *AlternativesFinder {
 	return &AlternativesFinder{childFinders: findersCopy, remainingFinders: findersCopy}
 }
 
+// LongestMatchAlternativesFinder is AlternativesFinder which finds the longest
+// data of the alternatives first, instead of the data of the first alternative.
+// e.g. "a" / "ab" finds "ab" from "ab".
+// Recalculate finds the other data of the alternatives, longer ones first.
+
+type LongestMatchAlternativesFinder struct {
+	childFinders []Finder
+	// candidateFinders are the copies of childFinders which found the syntax,
+	// and candidateEnds are their ends.
+	candidateFinders []Finder
+	candidateEnds    []int
+	foundIndex       int
+}
+
+func (finder *LongestMatchAlternativesFinder) Find(data []byte) (found bool, end int) {
+	finder.candidateFinders = []Finder{}
+	finder.candidateEnds = []int{}
+	finder.foundIndex = -1
+	for _, childFinder := range finder.childFinders {
+		candidateFinder := childFinder.Copy()
+		childFound, childEnd := candidateFinder.Find(data)
+		if childFound {
+			finder.candidateFinders = append(finder.candidateFinders, candidateFinder)
+			finder.candidateEnds = append(finder.candidateEnds, childEnd)
+		}
+	}
+	return finder.findLongest()
+}
+
+// findLongest chooses the candidate which found the longest data.
+func (finder *LongestMatchAlternativesFinder) findLongest() (found bool, end int) {
+	finder.foundIndex = -1
+	for i, candidateEnd := range finder.candidateEnds {
+		if finder.foundIndex < 0 || candidateEnd > end {
+			finder.foundIndex = i
+			end = candidateEnd
+		}
+	}
+	return finder.foundIndex >= 0, end
+}
+
+func (finder LongestMatchAlternativesFinder) Copy() Finder {
+	childFindersCopy := []Finder{}
+	for _, childFinder := range finder.childFinders {
+		childFindersCopy = append(childFindersCopy, childFinder.Copy())
+	}
+	return &LongestMatchAlternativesFinder{childFinders: childFindersCopy, foundIndex: -1}
+}
+
+func (finder *LongestMatchAlternativesFinder) Recalculate(data []byte) (found bool, end int) {
+	if finder.foundIndex < 0 {
+		return false, 0
+	}
+	// The found candidate might find other data. Replace its end with it, or
+	// remove the candidate.
+	i := finder.foundIndex
+	if candidateFinder, ok := finder.candidateFinders[i].(VariableFinder); ok {
+		otherFound, otherEnd := candidateFinder.Recalculate(data)
+		if otherFound {
+			finder.candidateEnds[i] = otherEnd
+			return finder.findLongest()
+		}
+	}
+	finder.candidateFinders = append(finder.candidateFinders[:i], finder.candidateFinders[i+1:]...)
+	finder.candidateEnds = append(finder.candidateEnds[:i], finder.candidateEnds[i+1:]...)
+	return finder.findLongest()
+}
+
+func (finder *LongestMatchAlternativesFinder) foundChildren(end int) []foundChild {
+	if finder.foundIndex < 0 {
+		return []foundChild{}
+	}
+	return []foundChild{{finder: finder.candidateFinders[finder.foundIndex], start: 0, end: end}}
+}
+
+func (finder *LongestMatchAlternativesFinder) setParser(p *parser) {
+	for _, childFinder := range finder.childFinders {
+		setParser(childFinder, p)
+	}
+}
+
+func NewLongestMatchAlternativesFinder(finders []Finder) *LongestMatchAlternativesFinder {
+	findersCopy := []Finder{}
+	for _, finder := range finders {
+		findersCopy = append(findersCopy, finder.Copy())
+	}
+	return &LongestMatchAlternativesFinder{childFinders: findersCopy, foundIndex: -1}
+}
+
 // RFC5234 - 3.4. Value Range Alternatives: %c##-##
 // A range of alternative numeric values can be specified compactly,
 // using a dash ("-") to indicate the range of alternative values.
@@ -326,53 +674,59 @@ func NewValueRangeAlternativesFinder(rangeStart byte, rangeEnd byte) *ValueRange
 // 3*3<element> allows exactly 3; and 1*2<element> allows one or two.
 
 type VariableRepetitionMinMaxFinder struct {
-	childFinder Finder
-	min         int
-	max         int
-	childEnds   []int
+	childFinder  Finder
+	min          int
+	max          int
+	childFinders []Finder
+	childEnds    []int
+	parser       *parser
 }
 
 func (finder *VariableRepetitionMinMaxFinder) Find(data []byte) (found bool, end int) {
-	remaining := data
-	childEnds := []int{}
-	for {
-		// If no more remaining return
-		if len(remaining) == 0 {
-			if len(finder.childEnds) == 0 && finder.min > 0 {
-				return false, 0
-			}
-			break
-		}
-
-		// If match count is bigger than max, return
-		if finder.max >= 0 && len(childEnds) >= finder.max {
-			break
-		}
+	finder.childFinders = []Finder{}
+	finder.childEnds = []int{}
+	finder.repeat(data)
+	if len(finder.childEnds) >= finder.min {
+		return true, finder.end()
+	}
+	finder.parser.backtrack(data)
+	return finder.Recalculate(data)
+}
 
+// repeat finds the syntax of childFinder after the current childEnds
+// until it reaches max or childFinder can not find it.
+func (finder *VariableRepetitionMinMaxFinder) repeat(data []byte) {
+	for finder.max < 0 || len(finder.childEnds) < finder.max {
+		start := finder.end()
 		// NOTE
 		// childFinder might have its state.
 		// It's dangerous to use the same childFinder in this for loop. So copy it.
 		childFinder := finder.childFinder.Copy()
-		childFound, childEnd := childFinder.Find(remaining)
-		if !childFound {
-			if len(finder.childEnds) == 0 && finder.min > 0 {
-				return false, 0
+		childFound, childEnd := childFinder.Find(data[start:])
+		// NOTE
+		// If childFinder finds the empty data, it finds the same data forever.
+		// It might find other data. e.g. *( *ab / a )
+		for childFound && childEnd == 0 && len(finder.childEnds) >= finder.min {
+			variableFinder, ok := childFinder.(VariableFinder)
+			if !ok {
+				return
 			}
-			break
-		}
-		remaining = remaining[childEnd:]
-		if len(childEnds) > 0 {
-			childEnd += childEnds[len(childEnds)-1]
+			childFound, childEnd = variableFinder.Recalculate(data[start:])
 		}
-		childEnds = append(childEnds, childEnd)
-		if len(childEnds) >= finder.min {
-			finder.childEnds = append(finder.childEnds, childEnd)
+		if !childFound {
+			return
 		}
+		finder.childFinders = append(finder.childFinders, childFinder)
+		finder.childEnds = append(finder.childEnds, start+childEnd)
 	}
-	if finder.min == 0 {
-		finder.childEnds = append([]int{0}, finder.childEnds...)
+}
+
+// end returns the end of the current repetitions.
+func (finder *VariableRepetitionMinMaxFinder) end() int {
+	if len(finder.childEnds) == 0 {
+		return 0
 	}
-	return true, finder.childEnds[len(finder.childEnds)-1]
+	return finder.childEnds[len(finder.childEnds)-1]
 }
 
 func (finder VariableRepetitionMinMaxFinder) Copy() Finder {
@@ -380,16 +734,57 @@ func (finder VariableRepetitionMinMaxFinder) Copy() Finder {
 		childFinder: finder.childFinder.Copy(),
 		min:         finder.min,
 		max:         finder.max,
-		childEnds:   append([]int{}, finder.childEnds...),
+		parser:      finder.parser,
 	}
 }
 
 func (finder *VariableRepetitionMinMaxFinder) Recalculate(data []byte) (found bool, end int) {
-	if len(finder.childEnds) == 1 {
-		return false, 0
+	for len(finder.childEnds) > 0 {
+		finder.parser.recalculateRepetition()
+		last := len(finder.childEnds) - 1
+		finder.childEnds = finder.childEnds[:last]
+		start := finder.end()
+
+		// NOTE
+		// The last childFinder might find other data. e.g. *( a *b ) b
+		// Try it before removing the last repetition.
+		otherFound, otherEnd := false, 0
+		if childFinder, ok := finder.childFinders[last].(VariableFinder); ok {
+			otherFound, otherEnd = childFinder.Recalculate(data[start:])
+		}
+		if !otherFound {
+			finder.childFinders = finder.childFinders[:last]
+			if len(finder.childEnds) >= finder.min {
+				return true, start
+			}
+			continue
+		}
+		finder.childEnds = append(finder.childEnds, start+otherEnd)
+		if otherEnd == 0 && last >= finder.min {
+			// The same as removing the last repetition. Recalculate it one more time.
+			continue
+		}
+		finder.repeat(data)
+		if len(finder.childEnds) >= finder.min {
+			return true, finder.end()
+		}
 	}
-	finder.childEnds = finder.childEnds[:len(finder.childEnds)-1]
-	return true, finder.childEnds[len(finder.childEnds)-1]
+	return false, 0
+}
+
+func (finder *VariableRepetitionMinMaxFinder) foundChildren(end int) []foundChild {
+	children := []foundChild{}
+	start := 0
+	for i, childEnd := range finder.childEnds {
+		children = append(children, foundChild{finder: finder.childFinders[i], start: start, end: childEnd})
+		start = childEnd
+	}
+	return children
+}
+
+func (finder *VariableRepetitionMinMaxFinder) setParser(p *parser) {
+	finder.parser = p
+	setParser(finder.childFinder, p)
 }
 
 func NewVariableRepetitionMinMaxFinder(min int, max int, finder Finder) *VariableRepetitionMinMaxFinder {
@@ -408,6 +803,147 @@ func NewVariableRepetitionFinder(finder Finder) *VariableRepetitionMinMaxFinder
 	return NewVariableRepetitionMinMaxFinder(0, -1, finder)
 }
 
+// LazyRepetitionMinMaxFinder finds the same syntax as VariableRepetitionMinMaxFinder,
+// but it finds the fewest repetitions first, and repeats once more at each
+// Recalculate. e.g. *OCTET CRLF finds the data up to the first CRLF with it.
+
+type LazyRepetitionMinMaxFinder struct {
+	childFinder  Finder
+	min          int
+	max          int
+	childFinders []Finder
+	childEnds    []int
+	parser       *parser
+}
+
+func (finder *LazyRepetitionMinMaxFinder) Find(data []byte) (found bool, end int) {
+	finder.childFinders = []Finder{}
+	finder.childEnds = []int{}
+	return finder.fill(data)
+}
+
+// fill repeats childFinder until it reaches min, backtracking if needed.
+func (finder *LazyRepetitionMinMaxFinder) fill(data []byte) (found bool, end int) {
+	for len(finder.childEnds) < finder.min {
+		start := finder.end()
+		childFinder := finder.childFinder.Copy()
+		childFound, childEnd := childFinder.Find(data[start:])
+		if childFound {
+			finder.childFinders = append(finder.childFinders, childFinder)
+			finder.childEnds = append(finder.childEnds, start+childEnd)
+			continue
+		}
+		finder.parser.backtrack(data)
+		if !finder.backtrack(data) {
+			return false, 0
+		}
+	}
+	return true, finder.end()
+}
+
+// backtrack replaces the last repetition with its other data. The repetitions
+// which can't find other data are removed.
+// It returns false if no repetition remains.
+func (finder *LazyRepetitionMinMaxFinder) backtrack(data []byte) bool {
+	for len(finder.childEnds) > 0 {
+		finder.parser.recalculateRepetition()
+		last := len(finder.childEnds) - 1
+		finder.childEnds = finder.childEnds[:last]
+		start := finder.end()
+		if childFinder, ok := finder.childFinders[last].(VariableFinder); ok {
+			otherFound, otherEnd := childFinder.Recalculate(data[start:])
+			// NOTE
+			// The empty repetition over min is the same as removing it, which
+			// has been found before. Skip it not to repeat it forever.
+			for otherFound && otherEnd == 0 && last >= finder.min {
+				otherFound, otherEnd = childFinder.Recalculate(data[start:])
+			}
+			if otherFound {
+				finder.childEnds = append(finder.childEnds, start+otherEnd)
+				return true
+			}
+		}
+		finder.childFinders = finder.childFinders[:last]
+	}
+	return false
+}
+
+// end returns the end of the current repetitions.
+func (finder *LazyRepetitionMinMaxFinder) end() int {
+	if len(finder.childEnds) == 0 {
+		return 0
+	}
+	return finder.childEnds[len(finder.childEnds)-1]
+}
+
+func (finder LazyRepetitionMinMaxFinder) Copy() Finder {
+	return &LazyRepetitionMinMaxFinder{
+		childFinder: finder.childFinder.Copy(),
+		min:         finder.min,
+		max:         finder.max,
+		parser:      finder.parser,
+	}
+}
+
+func (finder *LazyRepetitionMinMaxFinder) Recalculate(data []byte) (found bool, end int) {
+	if finder.max < 0 || len(finder.childEnds) < finder.max {
+		start := finder.end()
+		childFinder := finder.childFinder.Copy()
+		childFound, childEnd := childFinder.Find(data[start:])
+		// NOTE
+		// If childFinder finds the empty data, it finds the same data forever.
+		// It might find other data.
+		for childFound && childEnd == 0 {
+			variableFinder, ok := childFinder.(VariableFinder)
+			if !ok {
+				childFound = false
+				break
+			}
+			childFound, childEnd = variableFinder.Recalculate(data[start:])
+		}
+		if childFound {
+			finder.childFinders = append(finder.childFinders, childFinder)
+			finder.childEnds = append(finder.childEnds, start+childEnd)
+			return true, finder.end()
+		}
+	}
+	if !finder.backtrack(data) {
+		return false, 0
+	}
+	return finder.fill(data)
+}
+
+func (finder *LazyRepetitionMinMaxFinder) foundChildren(end int) []foundChild {
+	children := []foundChild{}
+	start := 0
+	for i, childEnd := range finder.childEnds {
+		children = append(children, foundChild{finder: finder.childFinders[i], start: start, end: childEnd})
+		start = childEnd
+	}
+	return children
+}
+
+func (finder *LazyRepetitionMinMaxFinder) setParser(p *parser) {
+	finder.parser = p
+	setParser(finder.childFinder, p)
+}
+
+func NewLazyRepetitionMinMaxFinder(min int, max int, finder Finder) *LazyRepetitionMinMaxFinder {
+	return &LazyRepetitionMinMaxFinder{min: min, max: max, childFinder: finder}
+}
+
+func NewLazyRepetitionMinFinder(min int, finder Finder) *LazyRepetitionMinMaxFinder {
+	return NewLazyRepetitionMinMaxFinder(min, -1, finder)
+}
+
+func NewLazyRepetitionMaxFinder(max int, finder Finder) *LazyRepetitionMinMaxFinder {
+	return NewLazyRepetitionMinMaxFinder(0, max, finder)
+}
+
+func NewLazyRepetitionFinder(finder Finder) *LazyRepetitionMinMaxFinder {
+	return NewLazyRepetitionMinMaxFinder(0, -1, finder)
+}
+
 // RFC5234 - 3.7. Specific Repetition: nRule
 // A rule of the form:
 //
@@ -448,6 +984,49 @@ func NewAlphaFinder() *AlternativesFinder {
 	})
 }
 
+// RFC5234 - B.1. Core Rules
+//
+//  BIT = "0" / "1"
+//
+
+func NewBitFinder() *ValueRangeAlternativesFinder {
+	return NewValueRangeAlternativesFinder('0', '1')
+}
+
+// RFC5234 - B.1. Core Rules
+//
+//  CHAR = %x01-7F
+//  ; any 7-bit US-ASCII character,
+//  ;  excluding NUL
+//
+
+func NewCharFinder() *ValueRangeAlternativesFinder {
+	return NewValueRangeAlternativesFinder(0x01, 0x7f)
+}
+
+// RFC5234 - B.1. Core Rules
+//
+//  CR = %x0D
+//  ; carriage return
+//
+
+func NewCrFinder() *ByteFinder {
+	return NewByteFinder(0x0d)
+}
+
+// RFC5234 - B.1. Core Rules
+//
+//  CTL = %x00-1F / %x7F
+//  ; controls
+//
+
+func NewCtlFinder() *AlternativesFinder {
+	return NewAlternativesFinder([]Finder{
+		NewValueRangeAlternativesFinder(0x00, 0x1f),
+		NewByteFinder(0x7f),
+	})
+}
+
 // RFC5234 - B.1. Core Rules
 //
 //  DIGIT = %x30-39 ; 0-9
@@ -494,6 +1073,37 @@ func NewHTabFinder() *ByteFinder {
 	return NewByteFinder(0x09)
 }
 
+// RFC5234 - B.1. Core Rules
+//
+//  LF = %x0A
+//  ; linefeed
+//
+
+func NewLfFinder() *ByteFinder {
+	return NewByteFinder(0x0a)
+}
+
+// RFC5234 - B.1. Core Rules
+//
+//  LWSP = *(WSP / CRLF WSP)
+//  ; Use of this linear-white-space rule
+//  ;  permits lines containing only white
+//  ;  space that are no longer legal in
+//  ;  mail headers and have caused
+//  ;  interoperability problems in other
+//  ;  contexts.
+//  ; Do not use when defining mail
+//  ;  headers and use with caution in
+//  ;  other contexts.
+//
+
+func NewLwspFinder() *VariableRepetitionMinMaxFinder {
+	return NewVariableRepetitionFinder(NewAlternativesFinder([]Finder{
+		NewWspFinder(),
+		NewConcatenationFinder([]Finder{NewCrLfFinder(), NewWspFinder()}),
+	}))
+}
+
 // RFC5234 - B.1. Core Rules
 //
 //  OCTET = %x00-FF
@@ -522,3 +1132,16 @@ func NewSpFinder() *ByteFinder {
 func NewVCharFinder() *ValueRangeAlternativesFinder {
 	return NewValueRangeAlternativesFinder(0x21, 0x7e)
 }
+
+// RFC5234 - B.1. Core Rules
+//
+//  WSP = SP / HTAB
+//  ; white space
+//
+
+func NewWspFinder() *AlternativesFinder {
+	return NewAlternativesFinder([]Finder{
+		NewSpFinder(),
+		NewHTabFinder(),
+	})
+}
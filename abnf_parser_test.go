@@ -1,7 +1,8 @@
 package abnfp
 
 import (
-	"os"
+	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -42,12 +43,6 @@ func execFinderTest(tests []TestCase, t *testing.T) {
 	}
 }
 
-func TestMain(m *testing.M) {
-	Debug = true
-	code := m.Run()
-	os.Exit(code)
-}
-
 func TestParse(t *testing.T) {
 	type TestCase struct {
 		testName          string
@@ -117,6 +112,233 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseAt(t *testing.T) {
+	type TestCase struct {
+		testName          string
+		data              []byte
+		start             int
+		finder            Finder
+		opts              []Option
+		expectedParsed    []byte
+		expectedRemaining []byte
+		expectedStart     int
+		expectedEnd       int
+		expectedRule      string
+		expectedTree      string
+		expectedErr       error
+	}
+
+	g, err := CompileGrammar([]byte("list = item *( \",\" item )\nitem = 1*ALPHA\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []TestCase{
+		{
+			testName:          "data: []byte(\"a;b,c;\"), parse list at 2",
+			data:              []byte("a;b,c;"),
+			start:             2,
+			finder:            g.Rule("list"),
+			expectedParsed:    []byte("b,c"),
+			expectedRemaining: []byte(";"),
+			expectedStart:     2,
+			expectedEnd:       5,
+			expectedRule:      "list",
+			expectedTree:      "<nil>",
+		},
+		{
+			testName:          "data: []byte(\"a;b,c;\"), parse list at 2 with tree",
+			data:              []byte("a;b,c;"),
+			start:             2,
+			finder:            g.Rule("list"),
+			opts:              []Option{WithTree()},
+			expectedParsed:    []byte("b,c"),
+			expectedRemaining: []byte(";"),
+			expectedStart:     2,
+			expectedEnd:       5,
+			expectedRule:      "list",
+			expectedTree:      "list:2-5(item:2-3 item:4-5)",
+		},
+		{
+			testName:          "data: []byte(\"a;b\"), parse \";\" item at 1 with tree",
+			data:              []byte("a;b"),
+			start:             1,
+			finder:            NewConcatenationFinder([]Finder{NewByteFinder(';'), g.Rule("item")}),
+			opts:              []Option{WithTree()},
+			expectedParsed:    []byte(";b"),
+			expectedRemaining: []byte(""),
+			expectedStart:     1,
+			expectedEnd:       3,
+			expectedRule:      "",
+			expectedTree:      ":1-3(item:2-3)",
+		},
+		{
+			testName:          "data: []byte(\"a;b\"), parse list at 1",
+			data:              []byte("a;b"),
+			start:             1,
+			finder:            g.Rule("list"),
+			opts:              []Option{WithTree()},
+			expectedParsed:    []byte(""),
+			expectedRemaining: []byte(";b"),
+			expectedStart:     1,
+			expectedEnd:       1,
+			expectedRule:      "list",
+			expectedTree:      "<nil>",
+			expectedErr:       ErrNotFound,
+		},
+		{
+			testName:          "data: []byte(\"a\"), parse list at 1",
+			data:              []byte("a"),
+			start:             1,
+			finder:            g.Rule("list"),
+			expectedParsed:    []byte(""),
+			expectedRemaining: []byte(""),
+			expectedStart:     1,
+			expectedEnd:       1,
+			expectedRule:      "list",
+			expectedTree:      "<nil>",
+			expectedErr:       ErrNotFound,
+		},
+		{
+			testName:          "data: []byte(\"a,b\"), parse list aborted",
+			data:              []byte("a,b"),
+			start:             0,
+			finder:            g.Rule("list"),
+			opts:              []Option{WithMaxSteps(1)},
+			expectedParsed:    []byte(""),
+			expectedRemaining: []byte("a,b"),
+			expectedStart:     0,
+			expectedEnd:       0,
+			expectedRule:      "list",
+			expectedTree:      "<nil>",
+			expectedErr:       ErrAborted,
+		},
+		{
+			testName:          "data: []byte(\"abc\"), parse list at 5",
+			data:              []byte("abc"),
+			start:             5,
+			finder:            g.Rule("list"),
+			expectedParsed:    []byte(""),
+			expectedRemaining: []byte(""),
+			expectedStart:     5,
+			expectedEnd:       5,
+			expectedRule:      "list",
+			expectedTree:      "<nil>",
+			expectedErr:       ErrOutOfRange,
+		},
+		{
+			testName:          "data: []byte(\"abc\"), parse list at -1",
+			data:              []byte("abc"),
+			start:             -1,
+			finder:            g.Rule("list"),
+			expectedParsed:    []byte(""),
+			expectedRemaining: []byte(""),
+			expectedStart:     -1,
+			expectedEnd:       -1,
+			expectedRule:      "list",
+			expectedTree:      "<nil>",
+			expectedErr:       ErrOutOfRange,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			result := ParseAt(testCase.data, testCase.start, testCase.finder, testCase.opts...)
+			sliceEquals(testCase.testName, t, testCase.expectedParsed, result.Parsed)
+			sliceEquals(testCase.testName, t, testCase.expectedRemaining, result.Remaining)
+			equals(testCase.testName, t, testCase.expectedStart, result.Start)
+			equals(testCase.testName, t, testCase.expectedEnd, result.End)
+			equals(testCase.testName, t, testCase.expectedRule, result.Rule)
+			equals(testCase.testName, t, testCase.expectedTree, formatNode(result.Tree))
+			if testCase.expectedErr == nil {
+				equals(testCase.testName, t, nil, result.Err)
+				return
+			}
+			equals(testCase.testName, t, true, errors.Is(result.Err, testCase.expectedErr))
+		})
+	}
+}
+
+// recalculatedEnds returns the ends found by Find and the following
+// Recalculates of finder, in the order of them.
+func recalculatedEnds(finder Finder, data []byte) []int {
+	ends := []int{}
+	found, end := finder.Find(data)
+	for found {
+		ends = append(ends, end)
+		variableFinder, ok := finder.(VariableFinder)
+		if !ok {
+			break
+		}
+		found, end = variableFinder.Recalculate(data)
+	}
+	return ends
+}
+
+// The repetitions and the alternatives recalculate the child Finder which
+// found the syntax, before they give up the child.
+func TestRecalculateChildren(t *testing.T) {
+	type TestCase struct {
+		testName     string
+		data         []byte
+		finder       Finder
+		expectedEnds []int
+	}
+
+	a := NewByteFinder('a')
+	b := NewByteFinder('b')
+	ab := NewBytesFinder([]byte("ab"))
+
+	tests := []TestCase{
+		{
+			testName:     "data: []byte(\"abab\"), recalculate *( a / ab )",
+			data:         []byte("abab"),
+			finder:       NewVariableRepetitionFinder(NewAlternativesFinder([]Finder{a, ab})),
+			expectedEnds: []int{1, 3, 4, 2, 0},
+		},
+		{
+			testName:     "data: []byte(\"abba\"), recalculate *( a *b )",
+			data:         []byte("abba"),
+			finder:       NewVariableRepetitionFinder(NewConcatenationFinder([]Finder{a, NewVariableRepetitionFinder(b)})),
+			expectedEnds: []int{4, 3, 2, 1, 0},
+		},
+		{
+			testName:     "data: []byte(\"aa\"), recalculate ( *a / b )",
+			data:         []byte("aa"),
+			finder:       NewAlternativesFinder([]Finder{NewVariableRepetitionFinder(a), b}),
+			expectedEnds: []int{2, 1, 0},
+		},
+		{
+			testName:     "data: []byte(\"aba\"), recalculate 2( a / ab )",
+			data:         []byte("aba"),
+			finder:       NewVariableRepetitionMinMaxFinder(2, 2, NewAlternativesFinder([]Finder{a, ab})),
+			expectedEnds: []int{3},
+		},
+		{
+			testName:     "data: []byte(\"aa\"), recalculate *( *a )",
+			data:         []byte("aa"),
+			finder:       NewVariableRepetitionFinder(NewVariableRepetitionFinder(a)),
+			expectedEnds: []int{2, 2, 1, 0},
+		},
+		{
+			testName: "data: []byte(\"abb\"), recalculate *( a *b ) b",
+			data:     []byte("abb"),
+			finder: NewConcatenationFinder([]Finder{
+				NewVariableRepetitionFinder(NewConcatenationFinder([]Finder{a, NewVariableRepetitionFinder(b)})),
+				b,
+			}),
+			expectedEnds: []int{3, 2},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			actualEnds := recalculatedEnds(testCase.finder.Copy(), testCase.data)
+			sliceEquals(testCase.testName, t, testCase.expectedEnds, actualEnds)
+		})
+	}
+}
+
 func TestByteFinder(t *testing.T) {
 	tests := []TestCase{
 		{
@@ -199,6 +421,54 @@ func TestBytesFinder(t *testing.T) {
 	execFinderTest(tests, t)
 }
 
+func TestCaseInsensitiveBytesFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}, find \"aB1\"",
+			data:          []byte{},
+			finder:        NewCaseInsensitiveBytesFinder([]byte("aB1")),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"ab\"), find \"aB1\"",
+			data:          []byte("ab"),
+			finder:        NewCaseInsensitiveBytesFinder([]byte("aB1")),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"ab1\"), find \"aB1\"",
+			data:          []byte("ab1"),
+			finder:        NewCaseInsensitiveBytesFinder([]byte("aB1")),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+		{
+			testName:      "data: []byte(\"AB1c\"), find \"aB1\"",
+			data:          []byte("AB1c"),
+			finder:        NewCaseInsensitiveBytesFinder([]byte("aB1")),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+		{
+			testName:      "data: []byte(\"Ab2\"), find \"aB1\"",
+			data:          []byte("Ab2"),
+			finder:        NewCaseInsensitiveBytesFinder([]byte("aB1")),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"a\"), find \"\"",
+			data:          []byte("a"),
+			finder:        NewCaseInsensitiveBytesFinder([]byte{}),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
 func TestCrLfFinder(t *testing.T) {
 	tests := []TestCase{
 		{
@@ -233,6 +503,72 @@ func TestCrLfFinder(t *testing.T) {
 	execFinderTest(tests, t)
 }
 
+func TestRuleFinder(t *testing.T) {
+	//
+	// parens = "(" *parens ")"
+	//
+	var newParensFinder func() *RuleFinder
+	newParensFinder = func() *RuleFinder {
+		return NewRuleFinder("parens", func() Finder {
+			return NewConcatenationFinder([]Finder{
+				NewByteFinder('('),
+				NewVariableRepetitionFinder(newParensFinder()),
+				NewByteFinder(')'),
+			})
+		})
+	}
+
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}, find parens",
+			data:          []byte{},
+			finder:        newParensFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"()\"), find parens",
+			data:          []byte("()"),
+			finder:        newParensFinder(),
+			expectedFound: true,
+			expectedEnd:   2,
+		},
+		{
+			testName:      "data: []byte(\"(()(()))()\"), find parens",
+			data:          []byte("(()(()))()"),
+			finder:        newParensFinder(),
+			expectedFound: true,
+			expectedEnd:   8,
+		},
+		{
+			testName:      "data: []byte(\"(()\"), find parens",
+			data:          []byte("(()"),
+			finder:        newParensFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		//
+		// Concatenation *rule "a"
+		//
+		// NOTE
+		// In this test case, ConcatenationFinder calls the Recalculate() of RuleFinder.
+		//
+		{
+			testName: "data: []byte(\"aa\"), find rule \"a\" where rule = *\"a\"",
+			data:     []byte("aa"),
+			finder: NewConcatenationFinder([]Finder{
+				NewRuleFinder("rule", func() Finder {
+					return NewVariableRepetitionFinder(NewByteFinder('a'))
+				}),
+				NewByteFinder('a'),
+			}),
+			expectedFound: true,
+			expectedEnd:   2,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
 func TestConcatenationFinder(t *testing.T) {
 
 	tests := []TestCase{
@@ -358,6 +694,65 @@ func TestConcatenationFinder(t *testing.T) {
 			expectedFound: true,
 			expectedEnd:   2,
 		},
+		//
+		// Concatenation (ALPHA *ALPHA) ALPHA
+		//
+		// NOTE
+		// In this test case, the last finder of the inner ConcatenationFinder is recalculated.
+		//
+		{
+			testName: "data: []byte(\"ab\"), find (ALPHA *ALPHA) ALPHA",
+			data:     []byte("ab"),
+			finder: NewConcatenationFinder([]Finder{
+				NewConcatenationFinder([]Finder{
+					NewAlphaFinder(),
+					NewVariableRepetitionFinder(NewAlphaFinder()),
+				}),
+				NewAlphaFinder(),
+			}),
+			expectedFound: true,
+			expectedEnd:   2,
+		},
+		//
+		// Concatenation *( a *b ) b
+		//
+		// NOTE
+		// In this test case, VariableRepetitionMinMaxFinder recalculates its last repetition.
+		//
+		{
+			testName: "data: []byte(\"abb\"), find *( a *b ) b",
+			data:     []byte("abb"),
+			finder: NewConcatenationFinder([]Finder{
+				NewVariableRepetitionFinder(
+					NewConcatenationFinder([]Finder{
+						NewByteFinder('a'),
+						NewVariableRepetitionFinder(NewByteFinder('b')),
+					}),
+				),
+				NewByteFinder('b'),
+			}),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+		//
+		// Concatenation ( *a / b ) a
+		//
+		// NOTE
+		// In this test case, AlternativesFinder recalculates the found alternative.
+		//
+		{
+			testName: "data: []byte(\"aa\"), find ( *a / b ) a",
+			data:     []byte("aa"),
+			finder: NewConcatenationFinder([]Finder{
+				NewAlternativesFinder([]Finder{
+					NewVariableRepetitionFinder(NewByteFinder('a')),
+					NewByteFinder('b'),
+				}),
+				NewByteFinder('a'),
+			}),
+			expectedFound: true,
+			expectedEnd:   2,
+		},
 	}
 	execFinderTest(tests, t)
 }
@@ -438,6 +833,89 @@ func TestAlternativesFinder(t *testing.T) {
 	execFinderTest(tests, t)
 }
 
+func TestLongestMatchAlternativesFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName: "data: []byte{}, find \"a\" / \"ab\"",
+			data:     []byte{},
+			finder: NewLongestMatchAlternativesFinder([]Finder{
+				NewByteFinder('a'),
+				NewBytesFinder([]byte("ab")),
+			}),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName: "data: []byte(\"ab\"), find \"a\" / \"ab\"",
+			data:     []byte("ab"),
+			finder: NewLongestMatchAlternativesFinder([]Finder{
+				NewByteFinder('a'),
+				NewBytesFinder([]byte("ab")),
+			}),
+			expectedFound: true,
+			expectedEnd:   2,
+		},
+		{
+			testName: "data: []byte(\"ac\"), find \"a\" / \"ab\"",
+			data:     []byte("ac"),
+			finder: NewLongestMatchAlternativesFinder([]Finder{
+				NewByteFinder('a'),
+				NewBytesFinder([]byte("ab")),
+			}),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+		//
+		// NOTE
+		// In this test case, "ab" is found first, then it is recalculated to find "a".
+		//
+		{
+			testName: "data: []byte(\"ab\"), find ( \"a\" / \"ab\" ) \"b\"",
+			data:     []byte("ab"),
+			finder: NewConcatenationFinder([]Finder{
+				NewLongestMatchAlternativesFinder([]Finder{
+					NewByteFinder('a'),
+					NewBytesFinder([]byte("ab")),
+				}),
+				NewByteFinder('b'),
+			}),
+			expectedFound: true,
+			expectedEnd:   2,
+		},
+		//
+		// NOTE
+		// In this test case, *a finds "aaa" first, then the shorter data of
+		// *a and "aa" are found in turn.
+		//
+		{
+			testName: "data: []byte(\"aaa\"), find ( *a / \"aa\" ) \"a\"",
+			data:     []byte("aaa"),
+			finder: NewConcatenationFinder([]Finder{
+				NewLongestMatchAlternativesFinder([]Finder{
+					NewVariableRepetitionFinder(NewByteFinder('a')),
+					NewBytesFinder([]byte("aa")),
+				}),
+				NewByteFinder('a'),
+			}),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+	}
+	execFinderTest(tests, t)
+
+	finder := NewLongestMatchAlternativesFinder([]Finder{
+		NewVariableRepetitionFinder(NewByteFinder('a')),
+		NewBytesFinder([]byte("aa")),
+	})
+	ends := []int{}
+	found, end := finder.Find([]byte("aaa"))
+	for found {
+		ends = append(ends, end)
+		found, end = finder.Recalculate([]byte("aaa"))
+	}
+	sliceEquals("Recalculate", t, []int{3, 2, 2, 1, 0}, ends)
+}
+
 func TestValueRangeAlternativesFinder(t *testing.T) {
 	tests := []TestCase{
 		{
@@ -519,6 +997,41 @@ func TestVariableRepetitionMinMaxFinder(t *testing.T) {
 			expectedFound: true,
 			expectedEnd:   3,
 		},
+		//
+		// NOTE
+		// In this test case, the first repetition is recalculated to find the second one.
+		//
+		{
+			testName: "data: []byte(\"aba\"), find \"2*2(a / ab)\"",
+			data:     []byte("aba"),
+			finder: NewVariableRepetitionMinMaxFinder(
+				2,
+				2,
+				NewAlternativesFinder([]Finder{
+					NewByteFinder('a'),
+					NewBytesFinder([]byte("ab")),
+				}),
+			),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+		//
+		// NOTE
+		// In this test case, *"ab" finds the empty data first, then the
+		// alternatives are recalculated to find "a".
+		//
+		{
+			testName: "data: []byte(\"a\"), find \"*(*\"ab\" / a)\"",
+			data:     []byte("a"),
+			finder: NewVariableRepetitionFinder(
+				NewAlternativesFinder([]Finder{
+					NewVariableRepetitionFinder(NewBytesFinder([]byte("ab"))),
+					NewByteFinder('a'),
+				}),
+			),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
 	}
 	execFinderTest(tests, t)
 }
@@ -669,10 +1182,124 @@ func TestVariableRepetitionFinder(t *testing.T) {
 			expectedFound: true,
 			expectedEnd:   5,
 		},
+		//
+		// repetition of the syntax which can be empty
+		//
+		{
+			testName: "data: []byte(\"b\"), find \"*(*a)\"",
+			data:     []byte("b"),
+			finder: NewVariableRepetitionFinder(
+				NewVariableRepetitionFinder(NewByteFinder('a')),
+			),
+			expectedFound: true,
+			expectedEnd:   0,
+		},
 	}
 	execFinderTest(tests, t)
 }
 
+func TestLazyRepetitionMinMaxFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"aaa\"), find lazy *a",
+			data:          []byte("aaa"),
+			finder:        NewLazyRepetitionFinder(NewByteFinder('a')),
+			expectedFound: true,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"aaa\"), find lazy 1*a",
+			data:          []byte("aaa"),
+			finder:        NewLazyRepetitionMinFinder(1, NewByteFinder('a')),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+		{
+			testName:      "data: []byte(\"b\"), find lazy 1*a",
+			data:          []byte("b"),
+			finder:        NewLazyRepetitionMinFinder(1, NewByteFinder('a')),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName: "data: []byte(\"ab\\r\\ncd\\r\\n\"), find lazy *OCTET CRLF",
+			data:     []byte("ab\r\ncd\r\n"),
+			finder: NewConcatenationFinder([]Finder{
+				NewLazyRepetitionFinder(NewOctetFinder()),
+				NewCrLfFinder(),
+			}),
+			expectedFound: true,
+			expectedEnd:   4,
+		},
+		//
+		// NOTE
+		// In this test case, the first repetition is recalculated to find "ab",
+		// because the second one can't be found after "a".
+		//
+		{
+			testName: "data: []byte(\"abab\"), find lazy 2*( a / ab )",
+			data:     []byte("abab"),
+			finder: NewLazyRepetitionMinFinder(2, NewAlternativesFinder([]Finder{
+				NewByteFinder('a'),
+				NewBytesFinder([]byte("ab")),
+			})),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+		{
+			testName: "data: []byte(\"aab\"), find lazy *[ a ] b",
+			data:     []byte("aab"),
+			finder: NewConcatenationFinder([]Finder{
+				NewLazyRepetitionFinder(NewOptionalSequenceFinder(NewByteFinder('a'))),
+				NewByteFinder('b'),
+			}),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+	}
+	execFinderTest(tests, t)
+
+	recalculateTests := []struct {
+		testName     string
+		data         []byte
+		finder       VariableFinder
+		expectedEnds []int
+	}{
+		{
+			testName:     "data: []byte(\"aaa\"), recalculate lazy *a",
+			data:         []byte("aaa"),
+			finder:       NewLazyRepetitionFinder(NewByteFinder('a')),
+			expectedEnds: []int{0, 1, 2, 3},
+		},
+		{
+			testName:     "data: []byte(\"aaa\"), recalculate lazy *2a",
+			data:         []byte("aaa"),
+			finder:       NewLazyRepetitionMaxFinder(2, NewByteFinder('a')),
+			expectedEnds: []int{0, 1, 2},
+		},
+		{
+			testName: "data: []byte(\"ab\"), recalculate lazy *( a / ab )",
+			data:     []byte("ab"),
+			finder: NewLazyRepetitionFinder(NewAlternativesFinder([]Finder{
+				NewByteFinder('a'),
+				NewBytesFinder([]byte("ab")),
+			})),
+			expectedEnds: []int{0, 1, 2},
+		},
+	}
+	for _, testCase := range recalculateTests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			ends := []int{}
+			found, end := testCase.finder.Find(testCase.data)
+			for found {
+				ends = append(ends, end)
+				found, end = testCase.finder.Recalculate(testCase.data)
+			}
+			sliceEquals(testCase.testName, t, testCase.expectedEnds, ends)
+		})
+	}
+}
+
 func TestSpecificRepetitionFinder(t *testing.T) {
 	tests := []TestCase{
 		{
@@ -838,6 +1465,128 @@ func TestAlphaFinder(t *testing.T) {
 	execFinderTest(tests, t)
 }
 
+func TestBitFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"0\"), find BIT",
+			data:          []byte("0"),
+			finder:        NewBitFinder(),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+		{
+			testName:      "data: []byte(\"1\"), find BIT",
+			data:          []byte("1"),
+			finder:        NewBitFinder(),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+		{
+			testName:      "data: []byte(\"2\"), find BIT",
+			data:          []byte("2"),
+			finder:        NewBitFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestCharFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{0x00}, find CHAR",
+			data:          []byte{0x00},
+			finder:        NewCharFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte{0x01}, find CHAR",
+			data:          []byte{0x01},
+			finder:        NewCharFinder(),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+		{
+			testName:      "data: []byte{0x7f}, find CHAR",
+			data:          []byte{0x7f},
+			finder:        NewCharFinder(),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+		{
+			testName:      "data: []byte{0x80}, find CHAR",
+			data:          []byte{0x80},
+			finder:        NewCharFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestCrFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}, find CR",
+			data:          []byte{},
+			finder:        NewCrFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"\\r\"), find CR",
+			data:          []byte("\r"),
+			finder:        NewCrFinder(),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+		{
+			testName:      "data: []byte(\"\\n\"), find CR",
+			data:          []byte("\n"),
+			finder:        NewCrFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestCtlFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{0x00}, find CTL",
+			data:          []byte{0x00},
+			finder:        NewCtlFinder(),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+		{
+			testName:      "data: []byte{0x1f}, find CTL",
+			data:          []byte{0x1f},
+			finder:        NewCtlFinder(),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+		{
+			testName:      "data: []byte{0x20}, find CTL",
+			data:          []byte{0x20},
+			finder:        NewCtlFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte{0x7f}, find CTL",
+			data:          []byte{0x7f},
+			finder:        NewCtlFinder(),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
 func TestDigitFinder(t *testing.T) {
 	tests := []TestCase{
 		{
@@ -1072,6 +1821,60 @@ func TestHTabFinder(t *testing.T) {
 	execFinderTest(tests, t)
 }
 
+func TestLfFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}, find LF",
+			data:          []byte{},
+			finder:        NewLfFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"\\n\"), find LF",
+			data:          []byte("\n"),
+			finder:        NewLfFinder(),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+		{
+			testName:      "data: []byte(\"\\r\"), find LF",
+			data:          []byte("\r"),
+			finder:        NewLfFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestLwspFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}, find LWSP",
+			data:          []byte{},
+			finder:        NewLwspFinder(),
+			expectedFound: true,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\" \\t\\r\\n a\"), find LWSP",
+			data:          []byte(" \t\r\n a"),
+			finder:        NewLwspFinder(),
+			expectedFound: true,
+			expectedEnd:   5,
+		},
+		{
+			testName:      "data: []byte(\" \\r\\na\"), find LWSP",
+			data:          []byte(" \r\na"),
+			finder:        NewLwspFinder(),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
 func TestFindOctet(t *testing.T) {
 	tests := []TestCase{
 		{
@@ -1173,3 +1976,42 @@ func TestFindVChar(t *testing.T) {
 	}
 	execFinderTest(tests, t)
 }
+
+func TestWspFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}, find WSP",
+			data:          []byte{},
+			finder:        NewWspFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte{0x20}, find WSP",
+			data:          []byte{0x20},
+			finder:        NewWspFinder(),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+		{
+			testName:      "data: []byte{0x09}, find WSP",
+			data:          []byte{0x09},
+			finder:        NewWspFinder(),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+		{
+			testName:      "data: []byte(\"a\"), find WSP",
+			data:          []byte("a"),
+			finder:        NewWspFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestParseAtOutOfRange(t *testing.T) {
+	result := ParseAt([]byte("abc"), 5, NewAlphaFinder())
+	equals("Err", t, "abnfp: offset out of range: start 5, length 3", fmt.Sprint(result.Err))
+}
@@ -0,0 +1,222 @@
+// Package abnftest tests the rules of ABNF grammars against sample files.
+//
+// The samples of a grammar are in a directory like
+//
+//	list.abnf
+//	valid/nested.txt
+//	valid/nested.txt.golden
+//	invalid/unclosed.txt
+//
+// The grammar is compiled from all the .abnf files in the directory.
+// Each file in valid must be found by the rule from all of its content, and
+// its parse tree must be the same as the golden file, which is the tree
+// encoded with abnfp.EncodeJSON. Each file in invalid must not be found from
+// all of its content.
+// The content is all the bytes of the file, so the samples must not end with
+// a newline unless the rule finds it.
+//
+// Run the tests with -abnftest.update to write the golden files of the valid
+// samples. The invalid samples have no golden files, so they are checked as
+// usual. The flag is namespaced not to conflict with the -update flags of the
+// other packages.
+package abnftest
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	abnfp "github.com/um7a/abnf-parser"
+)
+
+var update = flag.Bool("abnftest.update", false, "update the golden files of abnftest")
+
+// goldenSuffix is the suffix of the golden file of a sample.
+const goldenSuffix = ".golden"
+
+// Run tests the rule of the grammar in dir against the samples in it.
+// Each sample is run as a subtest named like "valid/nested.txt".
+// opts are used for all the parses.
+func Run(t *testing.T, dir string, rule string, opts ...abnfp.Option) {
+	t.Helper()
+	g, err := LoadGrammar(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.HasRule(rule) {
+		t.Fatalf("abnftest: rule %v is not defined in %v", rule, dir)
+	}
+	finder := fullMatchFinder(g, rule)
+	for _, kind := range []string{"valid", "invalid"} {
+		samples, err := samples(filepath.Join(dir, kind))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, sample := range samples {
+			t.Run(kind+"/"+filepath.Base(sample), func(t *testing.T) {
+				var err error
+				if kind == "valid" {
+					err = checkValid(sample, finder, *update, opts)
+				} else {
+					err = checkInvalid(sample, finder, opts)
+				}
+				if err != nil {
+					t.Error(err)
+				}
+			})
+		}
+	}
+}
+
+// LoadGrammar compiles all the .abnf files in dir in the order of their names.
+func LoadGrammar(dir string) (*abnfp.Grammar, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.abnf"))
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("abnftest: no .abnf files in %v", dir)
+	}
+	g := abnfp.NewGrammar()
+	for _, name := range names {
+		abnf, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := g.Compile(abnf); err != nil {
+			return nil, fmt.Errorf("%v: %w", name, err)
+		}
+	}
+	return g, nil
+}
+
+// fullMatchFinder returns the Finder which finds the syntax of the rule from
+// all of the data.
+func fullMatchFinder(g *abnfp.Grammar, rule string) abnfp.Finder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		g.Rule(rule),
+		abnfp.NewNotFinder(abnfp.NewOctetFinder()),
+	})
+}
+
+// samples returns the sample files in dir, without the golden files.
+// It returns no samples if dir doesn't exist.
+func samples(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	samples := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), goldenSuffix) {
+			continue
+		}
+		samples = append(samples, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(samples)
+	return samples, nil
+}
+
+// checkValid returns the error if finder doesn't find all of the sample, or
+// if the parse tree is not the same as the golden file.
+// If update is true, the golden file is written instead.
+func checkValid(sample string, finder abnfp.Finder, update bool, opts []abnfp.Option) error {
+	data, err := os.ReadFile(sample)
+	if err != nil {
+		return err
+	}
+	root, _, err := abnfp.ParseTree(data, finder, opts...)
+	if err != nil {
+		return fmt.Errorf("%v: the rule doesn't find all of the sample", sample)
+	}
+	// NOTE
+	// The root is the Node of the concatenation of the rule and the end of
+	// the data, so its only child is the rule.
+	tree := &bytes.Buffer{}
+	if err := abnfp.EncodeJSON(tree, root.Children[0], data); err != nil {
+		return err
+	}
+	golden := sample + goldenSuffix
+	if update {
+		return os.WriteFile(golden, tree.Bytes(), 0o644)
+	}
+	expected, err := os.ReadFile(golden)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%v: golden file %v doesn't exist, run with -abnftest.update to write it", sample, golden)
+	}
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(expected, tree.Bytes()) {
+		return fmt.Errorf("%v: the parse tree differs from %v (-expected +actual):\n%v", sample, golden, diff(string(expected), tree.String()))
+	}
+	return nil
+}
+
+// checkInvalid returns the error if finder finds all of the sample.
+// The invalid samples have no golden files, so they are not updated.
+func checkInvalid(sample string, finder abnfp.Finder, opts []abnfp.Option) error {
+	data, err := os.ReadFile(sample)
+	if err != nil {
+		return err
+	}
+	if _, _, err := abnfp.ParseTree(data, finder, opts...); err == nil {
+		return fmt.Errorf("%v: the rule finds all of the invalid sample", sample)
+	}
+	return nil
+}
+
+// diff returns the lines of expected and actual, where the lines only in
+// expected start with "-", the lines only in actual start with "+", and the
+// others start with " ".
+func diff(expected string, actual string) string {
+	a := strings.SplitAfter(expected, "\n")
+	b := strings.SplitAfter(actual, "\n")
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and
+	// b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	lines := strings.Builder{}
+	writeLine := func(prefix string, line string) {
+		if line == "" {
+			return
+		}
+		lines.WriteString(prefix + strings.TrimSuffix(line, "\n") + "\n")
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			writeLine(" ", a[i])
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			writeLine("-", a[i])
+			i++
+		default:
+			writeLine("+", b[j])
+			j++
+		}
+	}
+	return lines.String()
+}
@@ -0,0 +1,170 @@
+package abnftest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	Run(t, "testdata/list", "list")
+}
+
+func TestCheck(t *testing.T) {
+	type TestCase struct {
+		testName    string
+		check       func(sample string, update bool) error
+		sample      string
+		golden      string
+		update      bool
+		expectedErr string
+		// expectedGolden is the golden file after the check.
+		expectedGolden string
+	}
+
+	g, err := LoadGrammar("testdata/list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	finder := fullMatchFinder(g, "list")
+	valid := func(sample string, update bool) error { return checkValid(sample, finder, update, nil) }
+	invalid := func(sample string, update bool) error { return checkInvalid(sample, finder, nil) }
+	golden := "{\n" +
+		"  \"rule\": \"list\",\n" +
+		"  \"start\": 0,\n" +
+		"  \"end\": 1,\n" +
+		"  \"text\": \"1\",\n" +
+		"  \"children\": [\n" +
+		"    {\n" +
+		"      \"rule\": \"item\",\n" +
+		"      \"start\": 0,\n" +
+		"      \"end\": 1,\n" +
+		"      \"text\": \"1\",\n" +
+		"      \"children\": []\n" +
+		"    }\n" +
+		"  ]\n" +
+		"}\n"
+
+	tests := []TestCase{
+		{
+			testName:       "valid sample with golden file",
+			check:          valid,
+			sample:         "1",
+			golden:         golden,
+			expectedGolden: golden,
+		},
+		{
+			testName:       "valid sample without golden file",
+			check:          valid,
+			sample:         "1",
+			expectedErr:    "sample.txt: golden file sample.txt.golden doesn't exist, run with -abnftest.update to write it",
+			expectedGolden: "",
+		},
+		{
+			testName:       "valid sample with update",
+			check:          valid,
+			sample:         "1",
+			golden:         strings.Replace(golden, "\"1\"", "\"2\"", 1),
+			update:         true,
+			expectedGolden: golden,
+		},
+		{
+			testName: "valid sample with different golden file",
+			check:    valid,
+			sample:   "1",
+			golden:   strings.Replace(golden, "\"1\"", "\"2\"", 1),
+			expectedErr: "sample.txt: the parse tree differs from sample.txt.golden (-expected +actual):\n" +
+				" {\n" +
+				"   \"rule\": \"list\",\n" +
+				"   \"start\": 0,\n" +
+				"   \"end\": 1,\n" +
+				"-  \"text\": \"2\",\n" +
+				"+  \"text\": \"1\",\n" +
+				"   \"children\": [\n" +
+				"     {\n" +
+				"       \"rule\": \"item\",\n" +
+				"       \"start\": 0,\n" +
+				"       \"end\": 1,\n" +
+				"       \"text\": \"1\",\n" +
+				"       \"children\": []\n" +
+				"     }\n" +
+				"   ]\n" +
+				" }\n",
+			expectedGolden: strings.Replace(golden, "\"1\"", "\"2\"", 1),
+		},
+		{
+			testName:    "valid sample found partially",
+			check:       valid,
+			sample:      "1,",
+			expectedErr: "sample.txt: the rule doesn't find all of the sample",
+		},
+		{
+			testName: "invalid sample",
+			check:    invalid,
+			sample:   "1,",
+		},
+		{
+			testName: "invalid sample with update",
+			check:    invalid,
+			sample:   "1,",
+			update:   true,
+		},
+		{
+			testName:    "invalid sample found",
+			check:       invalid,
+			sample:      "(1)",
+			expectedErr: "sample.txt: the rule finds all of the invalid sample",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			dir := t.TempDir()
+			sample := filepath.Join(dir, "sample.txt")
+			if err := os.WriteFile(sample, []byte(testCase.sample), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if testCase.golden != "" {
+				if err := os.WriteFile(sample+goldenSuffix, []byte(testCase.golden), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			err := testCase.check(sample, testCase.update)
+			actualErr := ""
+			if err != nil {
+				actualErr = strings.ReplaceAll(err.Error(), dir+string(filepath.Separator), "")
+			}
+			if actualErr != testCase.expectedErr {
+				t.Errorf("%v: expected err: %q, actual: %q", testCase.testName, testCase.expectedErr, actualErr)
+			}
+			actualGolden, _ := os.ReadFile(sample + goldenSuffix)
+			if string(actualGolden) != testCase.expectedGolden {
+				t.Errorf("%v: expected golden file: %q, actual: %q", testCase.testName, testCase.expectedGolden, actualGolden)
+			}
+		})
+	}
+}
+
+func TestSamples(t *testing.T) {
+	actual, err := samples("testdata/list/valid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"testdata/list/valid/nested.txt", "testdata/list/valid/single.txt"}
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+
+	actual, err = samples("testdata/list/none")
+	if err != nil || len(actual) != 0 {
+		t.Errorf("expected no samples, actual: %v, %v", actual, err)
+	}
+}
+
+func TestLoadGrammarError(t *testing.T) {
+	_, err := LoadGrammar(t.TempDir())
+	if err == nil || !strings.HasPrefix(err.Error(), "abnftest: no .abnf files in ") {
+		t.Errorf("expected err: abnftest: no .abnf files in ..., actual: %v", err)
+	}
+}
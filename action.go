@@ -0,0 +1,93 @@
+package abnfp
+
+import "errors"
+
+var ErrNotFound = errors.New("abnfp: syntax not found")
+
+// Action converts the data found by a Finder to a value.
+// values are the values of the ActionFinders found inside the Finder,
+// in the order of the data.
+type Action func(data []byte, values []any) (value any, err error)
+
+// ActionFinder finds the same syntax as its child Finder.
+// When ParseValue finds the syntax, its Action is called with the found data.
+type ActionFinder struct {
+	childFinder Finder
+	action      Action
+}
+
+func (finder *ActionFinder) Find(data []byte) (found bool, end int) {
+	return finder.childFinder.Find(data)
+}
+
+func (finder ActionFinder) Copy() Finder {
+	return &ActionFinder{childFinder: finder.childFinder.Copy(), action: finder.action}
+}
+
+func (finder *ActionFinder) Recalculate(data []byte) (found bool, end int) {
+	variableFinder, ok := finder.childFinder.(VariableFinder)
+	if !ok {
+		return false, 0
+	}
+	return variableFinder.Recalculate(data)
+}
+
+func (finder *ActionFinder) setParser(p *parser) {
+	setParser(finder.childFinder, p)
+}
+
+func (finder *ActionFinder) foundChildren(end int) []foundChild {
+	return []foundChild{{finder: finder.childFinder, start: 0, end: end}}
+}
+
+func NewActionFinder(finder Finder, action Action) *ActionFinder {
+	return &ActionFinder{childFinder: finder.Copy(), action: action}
+}
+
+// ParseValue finds the syntax from the beginning of data, and returns the value
+// of the Action.
+// If finder is not an ActionFinder, value is the []any of the values of the
+// outermost ActionFinders inside finder.
+func ParseValue(data []byte, finder Finder, opts ...Option) (value any, remaining []byte, err error) {
+	p := newParser(data, opts)
+	finder = p.prepare(finder)
+	found, end, err := p.find(finder, data)
+	if err != nil {
+		return nil, data, err
+	}
+	if !found {
+		return nil, data, notFound(finder, opts)
+	}
+	values, err := evaluate(finder, data[:end])
+	if err != nil {
+		return nil, data, err
+	}
+	if _, ok := finder.(*ActionFinder); ok {
+		return values[0], data[end:], nil
+	}
+	return values, data[end:], nil
+}
+
+// evaluate returns the values of the outermost ActionFinders in finder.
+// data is the data found by finder.
+func evaluate(finder Finder, data []byte) ([]any, error) {
+	values := []any{}
+	if parent, ok := finder.(parentFinder); ok {
+		for _, child := range parent.foundChildren(len(data)) {
+			childValues, err := evaluate(child.finder, data[child.start:child.end])
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, childValues...)
+		}
+	}
+	actionFinder, ok := finder.(*ActionFinder)
+	if !ok {
+		return values, nil
+	}
+	value, err := actionFinder.action(data, values)
+	if err != nil {
+		return nil, err
+	}
+	return []any{value}, nil
+}
@@ -0,0 +1,148 @@
+package abnfp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func newNumberFinder() *ActionFinder {
+	return NewActionFinder(
+		NewVariableRepetitionMinFinder(1, NewDigitFinder()),
+		func(data []byte, values []any) (any, error) {
+			return strconv.Atoi(string(data))
+		},
+	)
+}
+
+func newStringAction() Action {
+	return func(data []byte, values []any) (any, error) {
+		return string(data), nil
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	type TestCase struct {
+		testName          string
+		data              []byte
+		finder            Finder
+		expectedValue     string
+		expectedRemaining []byte
+		expectedErr       error
+	}
+
+	errTest := errors.New("test error")
+
+	tests := []TestCase{
+		{
+			testName:          "data: []byte(\"123a\"), parse 1*DIGIT",
+			data:              []byte("123a"),
+			finder:            newNumberFinder(),
+			expectedValue:     "123",
+			expectedRemaining: []byte("a"),
+		},
+		{
+			testName:          "data: []byte(\"a\"), parse 1*DIGIT",
+			data:              []byte("a"),
+			finder:            newNumberFinder(),
+			expectedValue:     "<nil>",
+			expectedRemaining: []byte("a"),
+			expectedErr:       ErrNotFound,
+		},
+		{
+			testName: "data: []byte(\"1,23,456\"), parse sum of 1*DIGIT *( \",\" 1*DIGIT )",
+			data:     []byte("1,23,456"),
+			finder: NewActionFinder(
+				NewConcatenationFinder([]Finder{
+					newNumberFinder(),
+					NewVariableRepetitionFinder(
+						NewConcatenationFinder([]Finder{
+							NewByteFinder(','),
+							newNumberFinder(),
+						}),
+					),
+				}),
+				func(data []byte, values []any) (any, error) {
+					sum := 0
+					for _, value := range values {
+						sum += value.(int)
+					}
+					return sum, nil
+				},
+			),
+			expectedValue:     "480",
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName: "data: []byte(\"1,23\"), parse 1*DIGIT *( \",\" 1*DIGIT ) without ActionFinder on the top",
+			data:     []byte("1,23"),
+			finder: NewConcatenationFinder([]Finder{
+				newNumberFinder(),
+				NewVariableRepetitionFinder(
+					NewConcatenationFinder([]Finder{
+						NewByteFinder(','),
+						newNumberFinder(),
+					}),
+				),
+			}),
+			expectedValue:     "[1 23]",
+			expectedRemaining: []byte(""),
+		},
+		//
+		// NOTE
+		// In this test case, *ALPHA finds "ab" first, then it is recalculated to find "a".
+		// The value of "b" must belong to the last ALPHA.
+		//
+		{
+			testName: "data: []byte(\"ab\"), parse *ALPHA ALPHA",
+			data:     []byte("ab"),
+			finder: NewConcatenationFinder([]Finder{
+				NewActionFinder(
+					NewVariableRepetitionFinder(NewActionFinder(NewAlphaFinder(), newStringAction())),
+					func(data []byte, values []any) (any, error) {
+						return fmt.Sprintf("rep%v", values), nil
+					},
+				),
+				NewActionFinder(NewAlphaFinder(), newStringAction()),
+			}),
+			expectedValue:     "[rep[a] b]",
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName: "data: []byte(\"b\"), parse a / b",
+			data:     []byte("b"),
+			finder: NewAlternativesFinder([]Finder{
+				NewActionFinder(NewByteFinder('a'), func(data []byte, values []any) (any, error) {
+					return "a", nil
+				}),
+				NewActionFinder(NewByteFinder('b'), func(data []byte, values []any) (any, error) {
+					return "b", nil
+				}),
+			}),
+			expectedValue:     "[b]",
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName: "data: []byte(\"a\"), parse a with the error",
+			data:     []byte("a"),
+			finder: NewActionFinder(NewByteFinder('a'), func(data []byte, values []any) (any, error) {
+				return nil, errTest
+			}),
+			expectedValue:     "<nil>",
+			expectedRemaining: []byte("a"),
+			expectedErr:       errTest,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			value, remaining, err := ParseValue(testCase.data, testCase.finder)
+			equals(testCase.testName, t, testCase.expectedValue, fmt.Sprint(value))
+			sliceEquals(testCase.testName, t, testCase.expectedRemaining, remaining)
+			if !errors.Is(err, testCase.expectedErr) {
+				t.Errorf("%v: expected err: %v, actual err: %v", testCase.testName, testCase.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package abnfp
+
+// Capture is the data found by a CaptureFinder.
+// Span is the offsets in the data passed to ParseCaptures, and Value is the
+// data in it.
+type Capture struct {
+	Name string
+	Span
+	Value []byte
+}
+
+// CaptureFinder finds the same syntax as its child Finder.
+// When ParseCaptures finds the syntax, the found data is recorded with its name.
+type CaptureFinder struct {
+	name        string
+	childFinder Finder
+}
+
+func (finder *CaptureFinder) Find(data []byte) (found bool, end int) {
+	return finder.childFinder.Find(data)
+}
+
+func (finder CaptureFinder) Copy() Finder {
+	return &CaptureFinder{name: finder.name, childFinder: finder.childFinder.Copy()}
+}
+
+func (finder *CaptureFinder) Recalculate(data []byte) (found bool, end int) {
+	variableFinder, ok := finder.childFinder.(VariableFinder)
+	if !ok {
+		return false, 0
+	}
+	return variableFinder.Recalculate(data)
+}
+
+func (finder *CaptureFinder) setParser(p *parser) {
+	setParser(finder.childFinder, p)
+}
+
+func (finder *CaptureFinder) foundChildren(end int) []foundChild {
+	return []foundChild{{finder: finder.childFinder, start: 0, end: end}}
+}
+
+func (finder *CaptureFinder) Name() string {
+	return finder.name
+}
+
+func NewCaptureFinder(name string, finder Finder) *CaptureFinder {
+	return &CaptureFinder{name: name, childFinder: finder.Copy()}
+}
+
+// ParseCaptures finds the syntax from the beginning of data, and returns the
+// data found by the CaptureFinders inside finder in the order of their start.
+// The CaptureFinders discarded by backtracking are not included.
+func ParseCaptures(data []byte, finder Finder, opts ...Option) (captures []Capture, remaining []byte, err error) {
+	p := newParser(data, opts)
+	finder = p.prepare(finder)
+	found, end, err := p.find(finder, data)
+	if err != nil {
+		return nil, data, err
+	}
+	if !found {
+		return nil, data, notFound(finder, opts)
+	}
+	captures = collectCaptures(finder, data, 0, end, []Capture{})
+	return captures, data[end:], nil
+}
+
+// collectCaptures appends the captures in finder to captures.
+// finder found data[start:end].
+func collectCaptures(finder Finder, data []byte, start int, end int, captures []Capture) []Capture {
+	if captureFinder, ok := finder.(*CaptureFinder); ok {
+		captures = append(captures, Capture{
+			Name:  captureFinder.name,
+			Span:  Span{Start: start, End: end},
+			Value: data[start:end],
+		})
+	}
+	if parent, ok := finder.(parentFinder); ok {
+		for _, child := range parent.foundChildren(end - start) {
+			captures = collectCaptures(child.finder, data, start+child.start, start+child.end, captures)
+		}
+	}
+	return captures
+}
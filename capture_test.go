@@ -0,0 +1,101 @@
+package abnfp
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParseCaptures(t *testing.T) {
+	type TestCase struct {
+		testName          string
+		data              []byte
+		finder            Finder
+		expectedCaptures  []string
+		expectedRemaining []byte
+		expectedErr       error
+	}
+
+	tests := []TestCase{
+		{
+			testName: "data: []byte(\"example.com:80/\"), parse host \":\" port",
+			data:     []byte("example.com:80/"),
+			finder: NewConcatenationFinder([]Finder{
+				NewCaptureFinder("host", NewVariableRepetitionMinFinder(1, NewAlternativesFinder([]Finder{
+					NewAlphaFinder(),
+					NewByteFinder('.'),
+				}))),
+				NewByteFinder(':'),
+				NewCaptureFinder("port", NewVariableRepetitionFinder(NewDigitFinder())),
+			}),
+			expectedCaptures:  []string{"host:0-11:example.com", "port:12-14:80"},
+			expectedRemaining: []byte("/"),
+		},
+		{
+			testName:          "data: []byte(\"a\"), parse 1*DIGIT",
+			data:              []byte("a"),
+			finder:            NewCaptureFinder("digits", NewVariableRepetitionMinFinder(1, NewDigitFinder())),
+			expectedCaptures:  []string{},
+			expectedRemaining: []byte("a"),
+			expectedErr:       ErrNotFound,
+		},
+		{
+			testName: "data: []byte(\"ab\"), parse outer(inner(ALPHA) ALPHA)",
+			data:     []byte("ab"),
+			finder: NewCaptureFinder("outer", NewConcatenationFinder([]Finder{
+				NewCaptureFinder("inner", NewAlphaFinder()),
+				NewAlphaFinder(),
+			})),
+			expectedCaptures:  []string{"outer:0-2:ab", "inner:0-1:a"},
+			expectedRemaining: []byte(""),
+		},
+		//
+		// NOTE
+		// In this test case, *a finds "aaa" first, then it is recalculated to find "aa".
+		// The capture of the third "a" found by *a must be discarded.
+		//
+		{
+			testName: "data: []byte(\"aaa\"), parse *first(a) last(a)",
+			data:     []byte("aaa"),
+			finder: NewConcatenationFinder([]Finder{
+				NewVariableRepetitionFinder(NewCaptureFinder("first", NewByteFinder('a'))),
+				NewCaptureFinder("last", NewByteFinder('a')),
+			}),
+			expectedCaptures:  []string{"first:0-1:a", "first:1-2:a", "last:2-3:a"},
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName: "data: []byte(\"ab\"), parse first(a) first(c) / second(ab)",
+			data:     []byte("ab"),
+			finder: NewConcatenationFinder([]Finder{
+				NewAlternativesFinder([]Finder{
+					NewConcatenationFinder([]Finder{
+						NewCaptureFinder("first", NewByteFinder('a')),
+						NewCaptureFinder("first", NewByteFinder('c')),
+					}),
+					NewCaptureFinder("second", NewConcatenationFinder([]Finder{
+						NewByteFinder('a'),
+						NewByteFinder('b'),
+					})),
+				}),
+			}),
+			expectedCaptures:  []string{"second:0-2:ab"},
+			expectedRemaining: []byte(""),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			captures, remaining, err := ParseCaptures(testCase.data, testCase.finder)
+			actualCaptures := []string{}
+			for _, capture := range captures {
+				actualCaptures = append(actualCaptures, fmt.Sprintf("%v:%v-%v:%s", capture.Name, capture.Start, capture.End, capture.Value))
+			}
+			sliceEquals(testCase.testName, t, testCase.expectedCaptures, actualCaptures)
+			sliceEquals(testCase.testName, t, testCase.expectedRemaining, remaining)
+			if !errors.Is(err, testCase.expectedErr) {
+				t.Errorf("%v: expected err: %v, actual err: %v", testCase.testName, testCase.expectedErr, err)
+			}
+		})
+	}
+}
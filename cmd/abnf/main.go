@@ -0,0 +1,301 @@
+// Command abnf tests the input against a rule of an ABNF grammar.
+//
+// Usage:
+//
+//	abnf match [-g grammar.abnf | -rfc rfcNNNN.txt] -r rule [input]
+//	abnf parse [-g grammar.abnf | -rfc rfcNNNN.txt] -r rule [-format json|sexpr] [input]
+//	abnf trace [-g grammar.abnf | -rfc rfcNNNN.txt] -r rule [input]
+//	abnf check [-g grammar.abnf | -rfc rfcNNNN.txt]
+//	abnf gen   [-g grammar.abnf | -rfc rfcNNNN.txt] -r rule [-n count] [-seed seed] [-raw]
+//
+// The input is read from the file, or from the standard input if it is
+// omitted or "-". match, parse and trace find the syntax of the rule from all
+// of the input.
+//
+// match prints whether the input matches the rule.
+// parse prints the tree of the rules found in the input as JSON or
+// S-expression.
+// trace prints the events of the parse.
+// check prints the rules which are referred but not defined, and the prose-vals.
+// gen prints the random data of the rule, one per line, quoted as Go string
+// literals like "1,(2)" so that the data with newlines or non-printable bytes
+// fit in a line. With -raw, it prints the data as is, followed by a newline.
+//
+// The exit status is 0 if the input matches the rule or the grammar has no
+// errors, 1 if not, and 2 if the command fails.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	abnfp "github.com/um7a/abnf-parser"
+)
+
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitError   = 2
+)
+
+var (
+	// errNotFound is returned when the input doesn't match the rule.
+	errNotFound = errors.New("no match")
+	// errInvalid is returned when the grammar has errors.
+	errInvalid = errors.New("grammar has errors")
+)
+
+const usage = `usage:
+  abnf match [-g grammar.abnf | -rfc rfcNNNN.txt] -r rule [input]
+  abnf parse [-g grammar.abnf | -rfc rfcNNNN.txt] -r rule [-format json|sexpr] [input]
+  abnf trace [-g grammar.abnf | -rfc rfcNNNN.txt] -r rule [input]
+  abnf check [-g grammar.abnf | -rfc rfcNNNN.txt]
+  abnf gen   [-g grammar.abnf | -rfc rfcNNNN.txt] -r rule [-n count] [-seed seed] [-raw]
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// command is the options of a subcommand.
+type command struct {
+	name     string
+	flags    *flag.FlagSet
+	grammar  string
+	rfc      string
+	rule     string
+	format   string
+	count    int
+	raw      bool
+	seed     int64
+	maxDepth int
+	repeat   int
+	stdin    io.Reader
+	stdout   io.Writer
+	stderr   io.Writer
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitError
+	}
+	cmd := &command{name: args[0], stdin: stdin, stdout: stdout, stderr: stderr}
+	cmd.flags = flag.NewFlagSet("abnf "+cmd.name, flag.ContinueOnError)
+	cmd.flags.SetOutput(stderr)
+	cmd.flags.StringVar(&cmd.grammar, "g", "", "ABNF grammar file")
+	cmd.flags.StringVar(&cmd.rfc, "rfc", "", "RFC text file whose ABNF is used as the grammar")
+	var execute func() error
+	switch cmd.name {
+	case "match":
+		cmd.flags.StringVar(&cmd.rule, "r", "", "rule name")
+		execute = cmd.match
+	case "parse":
+		cmd.flags.StringVar(&cmd.rule, "r", "", "rule name")
+		cmd.flags.StringVar(&cmd.format, "format", "json", "format of the tree, json or sexpr")
+		execute = cmd.parse
+	case "trace":
+		cmd.flags.StringVar(&cmd.rule, "r", "", "rule name")
+		execute = cmd.trace
+	case "check":
+		execute = cmd.check
+	case "gen":
+		cmd.flags.StringVar(&cmd.rule, "r", "", "rule name")
+		cmd.flags.IntVar(&cmd.count, "n", 1, "number of samples")
+		cmd.flags.Int64Var(&cmd.seed, "seed", 1, "seed of the random numbers")
+		cmd.flags.IntVar(&cmd.maxDepth, "depth", 10, "maximum depth of the rules")
+		cmd.flags.IntVar(&cmd.repeat, "repeat", 5, "maximum extra repetitions")
+		cmd.flags.BoolVar(&cmd.raw, "raw", false, "print the data without quotes")
+		execute = cmd.gen
+	default:
+		fmt.Fprint(stderr, usage)
+		return exitError
+	}
+	if err := cmd.flags.Parse(args[1:]); err != nil {
+		return exitError
+	}
+	err := execute()
+	if errors.Is(err, errNotFound) || errors.Is(err, errInvalid) {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "abnf %v: %v\n", cmd.name, err)
+		return exitError
+	}
+	return exitOK
+}
+
+func (cmd *command) loadGrammar() (*abnfp.Grammar, error) {
+	switch {
+	case cmd.grammar != "" && cmd.rfc != "":
+		return nil, errors.New("-g and -rfc can't be used together")
+	case cmd.grammar != "":
+		abnf, err := os.ReadFile(cmd.grammar)
+		if err != nil {
+			return nil, err
+		}
+		return abnfp.CompileGrammar(abnf)
+	case cmd.rfc != "":
+		g, err := abnfp.LoadRFC(cmd.rfc)
+		var skipped *abnfp.SkippedRuleError
+		if errors.As(err, &skipped) {
+			// NOTE
+			// The rules which are not valid ABNF, like #list rules, are
+			// skipped. Warn them and use the other rules.
+			fmt.Fprintf(cmd.stderr, "warning: %v\n", err)
+			return g, nil
+		}
+		return g, err
+	}
+	return nil, errors.New("-g or -rfc is required")
+}
+
+// loadRule returns the Finder which finds the syntax of the rule from all of
+// the data.
+func (cmd *command) loadRule() (*abnfp.Grammar, abnfp.Finder, error) {
+	g, err := cmd.loadGrammar()
+	if err != nil {
+		return nil, nil, err
+	}
+	if cmd.rule == "" {
+		return nil, nil, errors.New("-r is required")
+	}
+	if !g.HasRule(cmd.rule) {
+		return nil, nil, fmt.Errorf("rule %v is not defined", cmd.rule)
+	}
+	return g, abnfp.NewConcatenationFinder([]abnfp.Finder{
+		g.Rule(cmd.rule),
+		abnfp.NewNotFinder(abnfp.NewOctetFinder()),
+	}), nil
+}
+
+func (cmd *command) readInput() ([]byte, error) {
+	switch cmd.flags.NArg() {
+	case 0:
+		return io.ReadAll(cmd.stdin)
+	case 1:
+		if cmd.flags.Arg(0) == "-" {
+			return io.ReadAll(cmd.stdin)
+		}
+		return os.ReadFile(cmd.flags.Arg(0))
+	}
+	return nil, errors.New("too many inputs")
+}
+
+func (cmd *command) match() error {
+	g, finder, err := cmd.loadRule()
+	if err != nil {
+		return err
+	}
+	data, err := cmd.readInput()
+	if err != nil {
+		return err
+	}
+	if _, _, err := abnfp.ParseTree(data, finder); err != nil {
+		return cmd.notFound(g, data)
+	}
+	fmt.Fprintln(cmd.stdout, "match")
+	return nil
+}
+
+// notFound returns errNotFound with the length of the longest data which the
+// rule finds from the beginning of data.
+func (cmd *command) notFound(g *abnfp.Grammar, data []byte) error {
+	parsed, _ := abnfp.Parse(data, g.Rule(cmd.rule), abnfp.WithLongestMatch())
+	return fmt.Errorf("%w: %v finds %v of %v bytes", errNotFound, cmd.rule, len(parsed), len(data))
+}
+
+func (cmd *command) parse() error {
+	g, finder, err := cmd.loadRule()
+	if err != nil {
+		return err
+	}
+	data, err := cmd.readInput()
+	if err != nil {
+		return err
+	}
+	root, _, err := abnfp.ParseTree(data, finder)
+	if err != nil {
+		return cmd.notFound(g, data)
+	}
+	// NOTE
+	// The root is the Node of the concatenation of the rule and the end of
+	// the data, so its only child is the rule.
+	switch cmd.format {
+	case "json":
+		return abnfp.EncodeJSON(cmd.stdout, root.Children[0], data)
+	case "sexpr":
+		return abnfp.EncodeSExpr(cmd.stdout, root.Children[0], data)
+	}
+	return fmt.Errorf("format %v is not supported", cmd.format)
+}
+
+func (cmd *command) trace() error {
+	g, finder, err := cmd.loadRule()
+	if err != nil {
+		return err
+	}
+	data, err := cmd.readInput()
+	if err != nil {
+		return err
+	}
+	if _, _, err := abnfp.ParseTree(data, finder, abnfp.WithTracer(abnfp.NewIndentTracer(cmd.stdout))); err != nil {
+		return cmd.notFound(g, data)
+	}
+	fmt.Fprintln(cmd.stdout, "match")
+	return nil
+}
+
+// check prints the errors and the warnings of the grammar.
+// The undefined rules are errors, and the prose-vals are warnings because
+// they find nothing in this command.
+func (cmd *command) check() error {
+	g, err := cmd.loadGrammar()
+	if err != nil {
+		return err
+	}
+	rules := []abnfp.Finder{}
+	for _, name := range g.RuleNames() {
+		rules = append(rules, g.Rule(name))
+	}
+	if err := abnfp.CheckProseVals(abnfp.NewAlternativesFinder(rules)); err != nil {
+		fmt.Fprintf(cmd.stdout, "warning: %v\n", err)
+	}
+	if err := g.Check(); err != nil {
+		fmt.Fprintf(cmd.stdout, "error: %v\n", err)
+		return errInvalid
+	}
+	fmt.Fprintf(cmd.stdout, "%v rules\n", len(rules))
+	return nil
+}
+
+func (cmd *command) gen() error {
+	g, err := cmd.loadGrammar()
+	if err != nil {
+		return err
+	}
+	if cmd.rule == "" {
+		return errors.New("-r is required")
+	}
+	if !g.HasRule(cmd.rule) {
+		return fmt.Errorf("rule %v is not defined", cmd.rule)
+	}
+	generator := abnfp.NewGenerator(cmd.seed, cmd.maxDepth, cmd.repeat)
+	for i := 0; i < cmd.count; i++ {
+		data, err := generator.Generate(g.Rule(cmd.rule))
+		if err != nil {
+			return err
+		}
+		if cmd.raw {
+			fmt.Fprintf(cmd.stdout, "%s\n", data)
+			continue
+		}
+		fmt.Fprintln(cmd.stdout, strconv.Quote(string(data)))
+	}
+	return nil
+}
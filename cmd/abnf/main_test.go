@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	type TestCase struct {
+		testName       string
+		args           []string
+		stdin          string
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+	}
+
+	tests := []TestCase{
+		{
+			testName:       "match",
+			args:           []string{"match", "-g", "testdata/list.abnf", "-r", "list"},
+			stdin:          "1,(2,3)",
+			expectedCode:   exitOK,
+			expectedStdout: "match\n",
+		},
+		{
+			testName:       "match partially",
+			args:           []string{"match", "-g", "testdata/list.abnf", "-r", "LIST", "-"},
+			stdin:          "1,(2,3)\n",
+			expectedCode:   exitFailure,
+			expectedStderr: "no match: LIST finds 7 of 8 bytes\n",
+		},
+		{
+			testName:       "match the rule of RFC",
+			args:           []string{"match", "-rfc", "../../testdata/rfc/rfc9999.txt", "-r", "message"},
+			stdin:          "HI foo\r\n",
+			expectedCode:   exitOK,
+			expectedStdout: "match\n",
+		},
+		{
+			testName:       "match undefined rule",
+			args:           []string{"match", "-g", "testdata/list.abnf", "-r", "lists"},
+			expectedCode:   exitError,
+			expectedStderr: "abnf match: rule lists is not defined\n",
+		},
+		{
+			testName:       "match without grammar",
+			args:           []string{"match", "-r", "list"},
+			expectedCode:   exitError,
+			expectedStderr: "abnf match: -g or -rfc is required\n",
+		},
+		{
+			testName:     "parse",
+			args:         []string{"parse", "-g", "testdata/list.abnf", "-r", "list"},
+			stdin:        "1,(2)",
+			expectedCode: exitOK,
+			expectedStdout: `{
+  "rule": "list",
+  "start": 0,
+  "end": 5,
+  "text": "1,(2)",
+  "children": [
+    {
+      "rule": "item",
+      "start": 0,
+      "end": 1,
+      "text": "1",
+      "children": []
+    },
+    {
+      "rule": "item",
+      "start": 2,
+      "end": 5,
+      "text": "(2)",
+      "children": [
+        {
+          "rule": "list",
+          "start": 3,
+          "end": 4,
+          "text": "2",
+          "children": [
+            {
+              "rule": "item",
+              "start": 3,
+              "end": 4,
+              "text": "2",
+              "children": []
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`,
+		},
+		{
+			testName:       "parse as S-expression",
+			args:           []string{"parse", "-g", "testdata/list.abnf", "-r", "list", "-format", "sexpr"},
+			stdin:          "1,(2)",
+			expectedCode:   exitOK,
+			expectedStdout: "(list 0 5 \"1,(2)\" (item 0 1 \"1\") (item 2 5 \"(2)\" (list 3 4 \"2\" (item 3 4 \"2\"))))\n",
+		},
+		{
+			testName:       "parse with unknown format",
+			args:           []string{"parse", "-g", "testdata/list.abnf", "-r", "list", "-format", "xml"},
+			stdin:          "1",
+			expectedCode:   exitError,
+			expectedStderr: "abnf parse: format xml is not supported\n",
+		},
+		{
+			testName:       "parse not matching",
+			args:           []string{"parse", "-g", "testdata/list.abnf", "-r", "list"},
+			stdin:          "x",
+			expectedCode:   exitFailure,
+			expectedStderr: "no match: list finds 0 of 1 bytes\n",
+		},
+		{
+			testName:     "trace",
+			args:         []string{"trace", "-g", "testdata/list.abnf", "-r", "item"},
+			stdin:        "1",
+			expectedCode: exitOK,
+			expectedStdout: "enter item at 0\n" +
+				"exit item at 0: found 0-1\n" +
+				"match\n",
+		},
+		{
+			testName:       "check",
+			args:           []string{"check", "-g", "testdata/list.abnf"},
+			expectedCode:   exitOK,
+			expectedStdout: "2 rules\n",
+		},
+		{
+			testName:     "check undefined rule",
+			args:         []string{"check", "-g", "testdata/undefined.abnf"},
+			expectedCode: exitFailure,
+			expectedStdout: "warning: abnfp: unbound prose-val: <other>\n" +
+				"error: abnfp: undefined rule: number\n",
+			expectedStderr: "grammar has errors\n",
+		},
+		{
+			testName:       "check skipped rule",
+			args:           []string{"check", "-rfc", "testdata/skipped.txt"},
+			expectedCode:   exitOK,
+			expectedStdout: "2 rules\n",
+			expectedStderr: "warning: abnfp: rule items is skipped: abnfp: invalid grammar: line 1, column 9: expected element, found '#'\n",
+		},
+		{
+			testName:       "check invalid grammar",
+			args:           []string{"check", "-g", "main_test.go"},
+			expectedCode:   exitError,
+			expectedStderr: "abnf check: abnfp: invalid grammar: line 1, column 9: expected \"=\" or \"=/\", found 'm'\n",
+		},
+		{
+			testName:     "unknown command",
+			args:         []string{"find"},
+			expectedCode: exitError,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			code := run(testCase.args, strings.NewReader(testCase.stdin), stdout, stderr)
+			if code != testCase.expectedCode {
+				t.Errorf("%v: expected code: %v, actual: %v (%v)", testCase.testName, testCase.expectedCode, code, stderr)
+			}
+			if stdout.String() != testCase.expectedStdout {
+				t.Errorf("%v: expected stdout: %q, actual: %q", testCase.testName, testCase.expectedStdout, stdout)
+			}
+			if testCase.expectedStderr != "" && stderr.String() != testCase.expectedStderr {
+				t.Errorf("%v: expected stderr: %q, actual: %q", testCase.testName, testCase.expectedStderr, stderr)
+			}
+		})
+	}
+}
+
+func TestRunGen(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	code := run([]string{"gen", "-g", "testdata/list.abnf", "-r", "list", "-n", "5", "-seed", "2"}, strings.NewReader(""), stdout, &bytes.Buffer{})
+	if code != exitOK {
+		t.Fatalf("expected code: %v, actual: %v", exitOK, code)
+	}
+	lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 samples, actual: %q", lines)
+	}
+	for _, line := range lines {
+		data, err := strconv.Unquote(line)
+		if err != nil {
+			t.Fatalf("generated data %v is not quoted: %v", line, err)
+		}
+		code := run([]string{"match", "-g", "testdata/list.abnf", "-r", "list"}, strings.NewReader(data), &bytes.Buffer{}, &bytes.Buffer{})
+		if code != exitOK {
+			t.Errorf("generated data %v doesn't match", line)
+		}
+	}
+
+	raw := &bytes.Buffer{}
+	code = run([]string{"gen", "-g", "testdata/list.abnf", "-r", "list", "-n", "5", "-seed", "2", "-raw"}, strings.NewReader(""), raw, &bytes.Buffer{})
+	if code != exitOK {
+		t.Fatalf("expected code: %v, actual: %v", exitOK, code)
+	}
+	for i, line := range strings.Split(strings.TrimSuffix(raw.String(), "\n"), "\n") {
+		if strconv.Quote(line) != lines[i] {
+			t.Errorf("expected raw data: %v, actual: %q", lines[i], line)
+		}
+	}
+}
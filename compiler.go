@@ -0,0 +1,564 @@
+package abnfp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidGrammar is returned when the ABNF text of a grammar is invalid.
+var ErrInvalidGrammar = errors.New("abnfp: invalid grammar")
+
+// CompileGrammar compiles the ABNF text of RFC5234 and RFC7405 to a Grammar.
+// See Grammar.Compile.
+func CompileGrammar(abnf []byte) (*Grammar, error) {
+	g := NewGrammar()
+	if err := g.Compile(abnf); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Compile compiles the ABNF text of RFC5234 and RFC7405, and adds the rules to g.
+// A rule defined with "=" overrides the rule already defined in g, but it
+// can't be defined twice in abnf. A rule extended with "=/" must be defined
+// in g or before it in abnf.
+// The lines can end with LF as well as CRLF.
+//
+// It returns ErrInvalidGrammar with the line and the column of the error, like
+//
+//	abnfp: invalid grammar: line 2, column 7: expected element
+//
+// and g is not changed then.
+func (g *Grammar) Compile(abnf []byte) error {
+	c := &abnfCompiler{data: abnf}
+	rules, err := c.compileRuleList()
+	if err != nil {
+		return err
+	}
+	defined := map[string]bool{}
+	for _, rule := range rules {
+		key := strings.ToLower(rule.name)
+		if !rule.incremental && defined[key] {
+			return c.errorAt(rule.pos, "rule %v is already defined", rule.name)
+		}
+		if _, ok := g.rules[key]; rule.incremental && !ok && !defined[key] {
+			return c.errorAt(rule.pos, "rule %v is not defined", rule.name)
+		}
+		defined[key] = true
+	}
+	for _, rule := range rules {
+		if rule.incremental {
+			g.defineAlternative(rule.name, rule.builder)
+			continue
+		}
+		g.define(rule.name, []ruleBuilder{rule.builder})
+	}
+	return nil
+}
+
+// compiledRule is a rule in the ABNF text.
+// incremental is true if it is defined with "=/".
+type compiledRule struct {
+	name        string
+	incremental bool
+	builder     ruleBuilder
+	pos         int
+}
+
+// abnfCompiler compiles the ABNF text with the recursive descent along
+// RFC5234 - 4. ABNF Definition of ABNF.
+type abnfCompiler struct {
+	data []byte
+	pos  int
+}
+
+func (c *abnfCompiler) errorAt(pos int, format string, args ...any) error {
+	line := bytes.Count(c.data[:pos], []byte("\n")) + 1
+	column := pos - (bytes.LastIndexByte(c.data[:pos], '\n') + 1) + 1
+	return fmt.Errorf("%w: line %d, column %d: %v", ErrInvalidGrammar, line, column, fmt.Sprintf(format, args...))
+}
+
+// expected returns the error that what is expected at the current position.
+func (c *abnfCompiler) expected(what string) error {
+	if c.eof() {
+		return c.errorAt(c.pos, "expected %v, found end of data", what)
+	}
+	return c.errorAt(c.pos, "expected %v, found %q", what, c.data[c.pos])
+}
+
+func (c *abnfCompiler) eof() bool {
+	return c.pos >= len(c.data)
+}
+
+func (c *abnfCompiler) peek() byte {
+	if c.eof() {
+		return 0
+	}
+	return c.data[c.pos]
+}
+
+func (c *abnfCompiler) consume(s string) bool {
+	if !bytes.HasPrefix(c.data[c.pos:], []byte(s)) {
+		return false
+	}
+	c.pos += len(s)
+	return true
+}
+
+func isAlpha(b byte) bool {
+	return ('A' <= b && b <= 'Z') || ('a' <= b && b <= 'z')
+}
+
+func isDigit(b byte) bool {
+	return '0' <= b && b <= '9'
+}
+
+func isWsp(b byte) bool {
+	return b == ' ' || b == '\t'
+}
+
+// compileRuleList compiles
+//
+//	rulelist = 1*( rule / (*c-wsp c-nl) )
+func (c *abnfCompiler) compileRuleList() ([]compiledRule, error) {
+	rules := []compiledRule{}
+	for !c.eof() {
+		start := c.pos
+		c.skipCWsp()
+		if c.eof() || c.consumeCNl() {
+			continue
+		}
+		c.pos = start
+		rule, err := c.compileRule()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// compileRule compiles
+//
+//	rule       = rulename defined-as elements c-nl
+//	defined-as = *c-wsp ("=" / "=/") *c-wsp
+//	elements   = alternation *c-wsp
+func (c *abnfCompiler) compileRule() (compiledRule, error) {
+	rule := compiledRule{pos: c.pos}
+	name, ok := c.compileRuleName()
+	if !ok {
+		return rule, c.expected("rulename")
+	}
+	rule.name = name
+	c.skipCWsp()
+	if c.consume("=/") {
+		rule.incremental = true
+	} else if !c.consume("=") {
+		return rule, c.expected("\"=\" or \"=/\"")
+	}
+	c.skipCWsp()
+	builder, err := c.compileAlternation()
+	if err != nil {
+		return rule, err
+	}
+	rule.builder = builder
+	c.skipCWsp()
+	if !c.eof() && !c.consumeCNl() {
+		return rule, c.expected("end of line")
+	}
+	return rule, nil
+}
+
+// compileRuleName compiles
+//
+//	rulename = ALPHA *(ALPHA / DIGIT / "-")
+//
+// NOTE
+// The rulename can also have "." followed by ALPHA, so that the rules imported
+// with a namespace, like rfc3986.host, can be referred.
+func (c *abnfCompiler) compileRuleName() (string, bool) {
+	if !isAlpha(c.peek()) {
+		return "", false
+	}
+	start := c.pos
+	for !c.eof() {
+		switch {
+		case isAlpha(c.peek()) || isDigit(c.peek()) || c.peek() == '-':
+			c.pos++
+		case c.peek() == '.' && c.pos+1 < len(c.data) && isAlpha(c.data[c.pos+1]):
+			c.pos += 2
+		default:
+			return string(c.data[start:c.pos]), true
+		}
+	}
+	return string(c.data[start:c.pos]), true
+}
+
+// skipCWsp skips *c-wsp, and returns the number of the skipped c-wsp.
+//
+//	c-wsp = WSP / (c-nl WSP)
+func (c *abnfCompiler) skipCWsp() int {
+	count := 0
+	for !c.eof() {
+		if isWsp(c.peek()) {
+			c.pos++
+			count++
+			continue
+		}
+		start := c.pos
+		if c.consumeCNl() && isWsp(c.peek()) {
+			c.pos++
+			count++
+			continue
+		}
+		c.pos = start
+		break
+	}
+	return count
+}
+
+// consumeCNl consumes c-nl, and returns false if there is not c-nl.
+// The comment at the end of data is a c-nl without CRLF.
+//
+//	c-nl    = comment / CRLF
+//	comment = ";" *(WSP / VCHAR) CRLF
+func (c *abnfCompiler) consumeCNl() bool {
+	start := c.pos
+	if c.consume(";") {
+		for !c.eof() && c.peek() != '\r' && c.peek() != '\n' {
+			c.pos++
+		}
+		if c.eof() {
+			return true
+		}
+	}
+	if c.consume("\r\n") || c.consume("\n") {
+		return true
+	}
+	c.pos = start
+	return false
+}
+
+// compileAlternation compiles
+//
+//	alternation = concatenation
+//	              *(*c-wsp "/" *c-wsp concatenation)
+func (c *abnfCompiler) compileAlternation() (ruleBuilder, error) {
+	builder, err := c.compileConcatenation()
+	if err != nil {
+		return nil, err
+	}
+	builders := []ruleBuilder{builder}
+	for {
+		start := c.pos
+		c.skipCWsp()
+		if !c.consume("/") {
+			c.pos = start
+			break
+		}
+		c.skipCWsp()
+		builder, err := c.compileConcatenation()
+		if err != nil {
+			return nil, err
+		}
+		builders = append(builders, builder)
+	}
+	if len(builders) == 1 {
+		return builders[0], nil
+	}
+	return func(rule func(name string) Finder) Finder {
+		return NewAlternativesFinder(buildAll(builders, rule))
+	}, nil
+}
+
+func buildAll(builders []ruleBuilder, rule func(name string) Finder) []Finder {
+	finders := []Finder{}
+	for _, builder := range builders {
+		finders = append(finders, builder(rule))
+	}
+	return finders
+}
+
+// compileConcatenation compiles
+//
+//	concatenation = repetition *(1*c-wsp repetition)
+func (c *abnfCompiler) compileConcatenation() (ruleBuilder, error) {
+	builder, err := c.compileRepetition()
+	if err != nil {
+		return nil, err
+	}
+	builders := []ruleBuilder{builder}
+	for {
+		start := c.pos
+		if c.skipCWsp() == 0 || !c.startsRepetition() {
+			c.pos = start
+			break
+		}
+		builder, err := c.compileRepetition()
+		if err != nil {
+			return nil, err
+		}
+		builders = append(builders, builder)
+	}
+	if len(builders) == 1 {
+		return builders[0], nil
+	}
+	return func(rule func(name string) Finder) Finder {
+		return NewConcatenationFinder(buildAll(builders, rule))
+	}, nil
+}
+
+func (c *abnfCompiler) startsRepetition() bool {
+	b := c.peek()
+	return !c.eof() && (isAlpha(b) || isDigit(b) || strings.IndexByte("*([\"%<", b) >= 0)
+}
+
+// compileRepetition compiles
+//
+//	repetition = [repeat] element
+//	repeat     = 1*DIGIT / (*DIGIT "*" *DIGIT)
+func (c *abnfCompiler) compileRepetition() (ruleBuilder, error) {
+	start := c.pos
+	min, hasMin, err := c.compileNumber(10)
+	if err != nil {
+		return nil, err
+	}
+	if !c.consume("*") {
+		builder, err := c.compileElement()
+		if err != nil || !hasMin {
+			return builder, err
+		}
+		return func(rule func(name string) Finder) Finder {
+			return NewSpecificRepetitionFinder(min, builder(rule))
+		}, nil
+	}
+	max, hasMax, err := c.compileNumber(10)
+	if err != nil {
+		return nil, err
+	}
+	if !hasMax {
+		max = -1
+	}
+	if hasMax && min > max {
+		return nil, c.errorAt(start, "repeat %v*%v is not valid", min, max)
+	}
+	builder, err := c.compileElement()
+	if err != nil {
+		return nil, err
+	}
+	return func(rule func(name string) Finder) Finder {
+		return NewVariableRepetitionMinMaxFinder(min, max, builder(rule))
+	}, nil
+}
+
+// compileNumber compiles the digits in base, and returns false if there are
+// no digits.
+func (c *abnfCompiler) compileNumber(base int) (int, bool, error) {
+	start := c.pos
+	for !c.eof() && isBaseDigit(c.peek(), base) {
+		c.pos++
+	}
+	if start == c.pos {
+		return 0, false, nil
+	}
+	number, err := strconv.ParseInt(string(c.data[start:c.pos]), base, 32)
+	if err != nil {
+		return 0, false, c.errorAt(start, "number %v is too large", string(c.data[start:c.pos]))
+	}
+	return int(number), true, nil
+}
+
+func isBaseDigit(b byte, base int) bool {
+	switch base {
+	case 2:
+		return b == '0' || b == '1'
+	case 16:
+		return isDigit(b) || ('A' <= b && b <= 'F') || ('a' <= b && b <= 'f')
+	}
+	return isDigit(b)
+}
+
+// compileElement compiles
+//
+//	element = rulename / group / option /
+//	          char-val / num-val / prose-val
+//	group   = "(" *c-wsp alternation *c-wsp ")"
+//	option  = "[" *c-wsp alternation *c-wsp "]"
+func (c *abnfCompiler) compileElement() (ruleBuilder, error) {
+	if name, ok := c.compileRuleName(); ok {
+		return func(rule func(name string) Finder) Finder {
+			return rule(name)
+		}, nil
+	}
+	switch {
+	case c.consume("("):
+		return c.compileGroup(")")
+	case c.consume("["):
+		builder, err := c.compileGroup("]")
+		if err != nil {
+			return nil, err
+		}
+		return func(rule func(name string) Finder) Finder {
+			return NewOptionalSequenceFinder(builder(rule))
+		}, nil
+	case c.peek() == '"':
+		return c.compileCharVal(false)
+	case c.consume("%s") || c.consume("%S"):
+		return c.compileCharVal(true)
+	case c.consume("%i") || c.consume("%I"):
+		return c.compileCharVal(false)
+	case c.consume("%"):
+		return c.compileNumVal()
+	case c.consume("<"):
+		return c.compileProseVal()
+	}
+	return nil, c.expected("element")
+}
+
+func (c *abnfCompiler) compileGroup(end string) (ruleBuilder, error) {
+	c.skipCWsp()
+	builder, err := c.compileAlternation()
+	if err != nil {
+		return nil, err
+	}
+	c.skipCWsp()
+	if !c.consume(end) {
+		return nil, c.expected(strconv.Quote(end))
+	}
+	return builder, nil
+}
+
+//	char-val = DQUOTE *(%x20-21 / %x23-7E) DQUOTE
+//
+// RFC7405 - 2.1. Formal Syntax
+//
+// compileCharVal compiles
+//
+//	char-val           =  case-insensitive-string /
+//	                      case-sensitive-string
+//	case-insensitive-string =
+//	                      [ "%i" ] quoted-string
+//	case-sensitive-string =
+//	                      "%s" quoted-string
+//
+// The prefixes are case-insensitive like the other strings of ABNF, so "%S"
+// and "%I" are also accepted.
+func (c *abnfCompiler) compileCharVal(caseSensitive bool) (ruleBuilder, error) {
+	if !c.consume("\"") {
+		return nil, c.expected("DQUOTE")
+	}
+	start := c.pos
+	for !c.eof() && c.peek() >= 0x20 && c.peek() <= 0x7e && c.peek() != '"' {
+		c.pos++
+	}
+	value := append([]byte{}, c.data[start:c.pos]...)
+	if !c.consume("\"") {
+		return nil, c.expected("DQUOTE")
+	}
+	hasLetter := false
+	for _, b := range value {
+		hasLetter = hasLetter || isAlpha(b)
+	}
+	return func(rule func(name string) Finder) Finder {
+		switch {
+		case len(value) == 0:
+			return NewConcatenationFinder([]Finder{})
+		case hasLetter && !caseSensitive:
+			return NewCaseInsensitiveBytesFinder(value)
+		case len(value) == 1:
+			return NewByteFinder(value[0])
+		}
+		return NewBytesFinder(value)
+	}, nil
+}
+
+// compileNumVal compiles
+//
+//	num-val = "%" (bin-val / dec-val / hex-val)
+//	bin-val = "b" 1*BIT
+//	          [ 1*("." 1*BIT) / ("-" 1*BIT) ]
+//	dec-val = "d" 1*DIGIT
+//	          [ 1*("." 1*DIGIT) / ("-" 1*DIGIT) ]
+//	hex-val = "x" 1*HEXDIG
+//	          [ 1*("." 1*HEXDIG) / ("-" 1*HEXDIG) ]
+func (c *abnfCompiler) compileNumVal() (ruleBuilder, error) {
+	base := 0
+	switch {
+	case c.consume("b") || c.consume("B"):
+		base = 2
+	case c.consume("d") || c.consume("D"):
+		base = 10
+	case c.consume("x") || c.consume("X"):
+		base = 16
+	default:
+		return nil, c.expected("\"b\", \"d\" or \"x\"")
+	}
+	start := c.pos
+	first, err := c.compileByteValue(base)
+	if err != nil {
+		return nil, err
+	}
+	if c.consume("-") {
+		last, err := c.compileByteValue(base)
+		if err != nil {
+			return nil, err
+		}
+		if first > last {
+			return nil, c.errorAt(start, "range %v is not valid", string(c.data[start:c.pos]))
+		}
+		return func(rule func(name string) Finder) Finder {
+			return NewValueRangeAlternativesFinder(first, last)
+		}, nil
+	}
+	values := []byte{first}
+	for c.consume(".") {
+		value, err := c.compileByteValue(base)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return func(rule func(name string) Finder) Finder {
+		if len(values) == 1 {
+			return NewByteFinder(values[0])
+		}
+		return NewBytesFinder(values)
+	}, nil
+}
+
+// compileByteValue compiles a value of num-val.
+// The values are bytes, because Finders find the syntax of bytes.
+func (c *abnfCompiler) compileByteValue(base int) (byte, error) {
+	start := c.pos
+	value, ok, err := c.compileNumber(base)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, c.expected("value")
+	}
+	if value > 0xff {
+		return 0, c.errorAt(start, "value %v is over a byte", string(c.data[start:c.pos]))
+	}
+	return byte(value), nil
+}
+
+// compileProseVal compiles
+//
+//	prose-val = "<" *(%x20-3D / %x3F-7E) ">"
+func (c *abnfCompiler) compileProseVal() (ruleBuilder, error) {
+	start := c.pos
+	for !c.eof() && c.peek() >= 0x20 && c.peek() <= 0x7e && c.peek() != '>' {
+		c.pos++
+	}
+	description := string(c.data[start:c.pos])
+	if !c.consume(">") {
+		return nil, c.expected("\">\"")
+	}
+	return func(rule func(name string) Finder) Finder {
+		return NewProseValFinder(description)
+	}, nil
+}
@@ -0,0 +1,260 @@
+package abnfp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompileGrammar(t *testing.T) {
+	type TestCase struct {
+		testName          string
+		abnf              string
+		rule              string
+		data              []byte
+		opts              []Option
+		expectedParsed    []byte
+		expectedRemaining []byte
+	}
+
+	uri := "; The subset of URI.\r\n" +
+		"uri    = scheme \":\" host [ \":\" port ]\r\n" +
+		"scheme = ALPHA *( ALPHA / DIGIT / \"+\" / \"-\" / \".\" )\r\n" +
+		"\r\n" +
+		"host   = 1*( ALPHA / DIGIT / \".\" ) ; reg-name\r\n" +
+		"port   = *DIGIT\r\n"
+
+	tests := []TestCase{
+		{
+			testName:          "data: []byte(\"http:example.com:80/\"), parse uri",
+			abnf:              uri,
+			rule:              "uri",
+			data:              []byte("http:example.com:80/"),
+			expectedParsed:    []byte("http:example.com:80"),
+			expectedRemaining: []byte("/"),
+		},
+		{
+			testName:          "data: []byte(\"http:example.com:80/\"), parse URI",
+			abnf:              uri,
+			rule:              "URI",
+			data:              []byte("http:example.com:80/"),
+			expectedParsed:    []byte("http:example.com:80"),
+			expectedRemaining: []byte("/"),
+		},
+		{
+			testName:          "data: []byte(\"get\"), parse method = \"GET\"",
+			abnf:              "method = \"GET\"\n",
+			rule:              "method",
+			data:              []byte("get"),
+			expectedParsed:    []byte("get"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName:          "data: []byte(\"get\"), parse method = %s\"GET\"",
+			abnf:              "method = %s\"GET\"\n",
+			rule:              "method",
+			data:              []byte("get"),
+			expectedParsed:    []byte(""),
+			expectedRemaining: []byte("get"),
+		},
+		{
+			testName:          "data: []byte(\"get\"), parse method = %S\"GET\"",
+			abnf:              "method = %S\"GET\"\n",
+			rule:              "method",
+			data:              []byte("get"),
+			expectedParsed:    []byte(""),
+			expectedRemaining: []byte("get"),
+		},
+		{
+			testName:          "data: []byte(\"GET\"), parse method = %I\"get\"",
+			abnf:              "method = %I\"get\"\n",
+			rule:              "method",
+			data:              []byte("GET"),
+			expectedParsed:    []byte("GET"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName:          "data: []byte(\"abcd\"), parse a = %x61.62 b.c",
+			abnf:              "a = %x61.62 b.c\nb.c = \"c\"\n",
+			rule:              "a",
+			data:              []byte("abcd"),
+			expectedParsed:    []byte("abc"),
+			expectedRemaining: []byte("d"),
+		},
+		{
+			testName:          "data: []byte(\"GET\"), parse method = %i\"get\"",
+			abnf:              "method = %i\"get\"\n",
+			rule:              "method",
+			data:              []byte("GET"),
+			expectedParsed:    []byte("GET"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName:          "data: []byte(\"ab\"), parse a = \"\" \"a\"",
+			abnf:              "a = \"\" \"a\"",
+			rule:              "a",
+			data:              []byte("ab"),
+			expectedParsed:    []byte("a"),
+			expectedRemaining: []byte("b"),
+		},
+		{
+			testName:          "data: []byte(\"A\\r\\n\\n\"), parse line = %x41-5A %d13.10 %b1010",
+			abnf:              "line = %x41-5A %d13.10 %b1010\n",
+			rule:              "line",
+			data:              []byte("A\r\n\n"),
+			expectedParsed:    []byte("A\r\n\n"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName:          "data: []byte(\"aaaa\"), parse a = 2*3\"a\"",
+			abnf:              "a = 2*3\"a\"\n",
+			rule:              "a",
+			data:              []byte("aaaa"),
+			expectedParsed:    []byte("aaa"),
+			expectedRemaining: []byte("a"),
+		},
+		{
+			testName:          "data: []byte(\"aaaa\"), parse a = 2\"a\"",
+			abnf:              "a = 2\"a\"\n",
+			rule:              "a",
+			data:              []byte("aaaa"),
+			expectedParsed:    []byte("aa"),
+			expectedRemaining: []byte("aa"),
+		},
+		{
+			testName:          "data: []byte(\"aaab\"), parse a = *2\"a\" \"ab\"",
+			abnf:              "a = *2\"a\" \"ab\"\n",
+			rule:              "a",
+			data:              []byte("aaab"),
+			expectedParsed:    []byte("aaab"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName: "data: []byte(\"(a(b))\"), parse recursive rule on continuation lines",
+			abnf: "comment = \"(\"\n" +
+				"          *( ALPHA / comment ) ; comment in the rule\n" +
+				"          \")\"\n",
+			rule:              "comment",
+			data:              []byte("(a(b))"),
+			expectedParsed:    []byte("(a(b))"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName:          "data: []byte(\"b\"), parse a = \"a\", a =/ \"b\"",
+			abnf:              "a = \"a\"\na =/ \"b\"\n",
+			rule:              "a",
+			data:              []byte("b"),
+			expectedParsed:    []byte("b"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName:          "data: []byte(\"1a\"), parse a = <digits> ALPHA",
+			abnf:              "a = <digits> ALPHA\n",
+			rule:              "a",
+			data:              []byte("1a"),
+			opts:              []Option{WithProseVal("digits", NewVariableRepetitionMinFinder(1, NewDigitFinder()))},
+			expectedParsed:    []byte("1a"),
+			expectedRemaining: []byte(""),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			g, err := CompileGrammar([]byte(testCase.abnf))
+			if err != nil {
+				t.Fatalf("%v: %v", testCase.testName, err)
+			}
+			actualParsed, actualRemaining := Parse(testCase.data, g.Rule(testCase.rule), testCase.opts...)
+			sliceEquals(testCase.testName, t, testCase.expectedParsed, actualParsed)
+			sliceEquals(testCase.testName, t, testCase.expectedRemaining, actualRemaining)
+		})
+	}
+}
+
+func TestCompileGrammarError(t *testing.T) {
+	type TestCase struct {
+		testName    string
+		abnf        string
+		expectedErr string
+	}
+
+	tests := []TestCase{
+		{
+			testName:    "no defined-as",
+			abnf:        "a \"a\"\n",
+			expectedErr: "abnfp: invalid grammar: line 1, column 3: expected \"=\" or \"=/\", found '\"'",
+		},
+		{
+			testName:    "no element",
+			abnf:        "a = \"a\"\nb = / \"b\"\n",
+			expectedErr: "abnfp: invalid grammar: line 2, column 5: expected element, found '/'",
+		},
+		{
+			testName:    "indented rule",
+			abnf:        "a = \"a\"\n b = \"b\"\n",
+			expectedErr: "abnfp: invalid grammar: line 2, column 4: expected end of line, found '='",
+		},
+		{
+			testName:    "unclosed group",
+			abnf:        "a = ( \"a\" / \"b\"",
+			expectedErr: "abnfp: invalid grammar: line 1, column 16: expected \")\", found end of data",
+		},
+		{
+			testName:    "unclosed char-val",
+			abnf:        "a = \"a\n",
+			expectedErr: "abnfp: invalid grammar: line 1, column 7: expected DQUOTE, found '\\n'",
+		},
+		{
+			testName:    "value over a byte",
+			abnf:        "a = %x100\n",
+			expectedErr: "abnfp: invalid grammar: line 1, column 7: value 100 is over a byte",
+		},
+		{
+			testName:    "invalid range",
+			abnf:        "a = %x42-41\n",
+			expectedErr: "abnfp: invalid grammar: line 1, column 7: range 42-41 is not valid",
+		},
+		{
+			testName:    "invalid repeat",
+			abnf:        "a = 3*2\"a\"\n",
+			expectedErr: "abnfp: invalid grammar: line 1, column 5: repeat 3*2 is not valid",
+		},
+		{
+			testName:    "rule defined twice",
+			abnf:        "a = \"a\"\r\nA = \"b\"\r\n",
+			expectedErr: "abnfp: invalid grammar: line 2, column 1: rule A is already defined",
+		},
+		{
+			testName:    "undefined rule extended",
+			abnf:        "a = \"a\"\nb =/ \"b\"\n",
+			expectedErr: "abnfp: invalid grammar: line 2, column 1: rule b is not defined",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			g, err := CompileGrammar([]byte(testCase.abnf))
+			if err == nil {
+				t.Fatalf("%v: expected err: %v, actual: %v", testCase.testName, testCase.expectedErr, g.RuleNames())
+			}
+			equals(testCase.testName, t, true, errors.Is(err, ErrInvalidGrammar))
+			equals(testCase.testName, t, testCase.expectedErr, err.Error())
+		})
+	}
+}
+
+func TestCompileFormattedRules(t *testing.T) {
+	abnf := "comment = \"(\" *( ctext / comment ) \")\"\n" +
+		"ctext = %x21-27 / %x2A-5B / %x5D-7E\n"
+	g, err := CompileGrammar([]byte(abnf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	formatted := FormatRules(g.Rule("comment"))
+	equals("FormatRules", t, "comment = %x28 *( ctext / comment ) %x29\n"+
+		"ctext = %x21-27 / %x2A-5B / %x5D-7E\n", formatted)
+	recompiled, err := CompileGrammar([]byte(formatted))
+	if err != nil {
+		t.Fatal(err)
+	}
+	equals("FormatRules of recompiled", t, formatted, FormatRules(recompiled.Rule("comment")))
+}
@@ -0,0 +1,674 @@
+package abnfp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MatchMode is the data which a DFAFinder finds first when it can find some
+// data.
+//
+// NOTE
+// A DFA can't tell which alternative or how many repetitions found the data,
+// so LongestMatch and ShortestMatch are not the order of the backtracking
+// Finders, which find the data of the first alternative first, e.g. "a" / "ab"
+// finds "a" from "ab". FirstMatch follows the NFA in the order of the
+// alternatives and the repetitions instead of the DFA, so it takes time linear
+// in the data times the size of the NFA.
+type MatchMode int
+
+const (
+	// LongestMatch finds the longest data first.
+	LongestMatch MatchMode = iota
+	// ShortestMatch finds the shortest data first.
+	ShortestMatch
+	// FirstMatch finds the data in the order in which the backtracking Finder
+	// finds them, so it finds the same data first as the backtracking Finder.
+	// LongestMatchAlternativesFinders can't be found in this order.
+	FirstMatch
+)
+
+// The maximum number of the states of an NFA and a DFA.
+// The larger automata are not built, and the backtracking Finders are used.
+const (
+	maxNfaStates = 10000
+	maxDfaStates = 10000
+)
+
+// nfaState is a state of a Thompson NFA.
+// It moves to next with a byte between low and high, if next is not negative.
+// If epsilons[i] finishes an iteration of a repetition, iterations[i] is the
+// state where the iteration starts, otherwise -1.
+type nfaState struct {
+	epsilons   []int
+	iterations []int
+	low        byte
+	high       byte
+	next       int
+}
+
+type nfaBuilder struct {
+	states []nfaState
+	rules  []string
+	mode   MatchMode
+}
+
+func (builder *nfaBuilder) newState() (int, error) {
+	if len(builder.states) >= maxNfaStates {
+		return 0, fmt.Errorf("%w: NFA has more than %v states", ErrNotRegular, maxNfaStates)
+	}
+	builder.states = append(builder.states, nfaState{next: -1})
+	return len(builder.states) - 1, nil
+}
+
+func (builder *nfaBuilder) addEpsilon(from int, to int) {
+	builder.addIterationEpsilon(from, to, -1)
+}
+
+// addIterationEpsilon adds the epsilon which finishes the iteration of a
+// repetition started at start.
+func (builder *nfaBuilder) addIterationEpsilon(from int, to int, start int) {
+	builder.states[from].epsilons = append(builder.states[from].epsilons, to)
+	builder.states[from].iterations = append(builder.states[from].iterations, start)
+}
+
+func (builder *nfaBuilder) addRange(from int, low byte, high byte) (int, error) {
+	to, err := builder.newState()
+	if err != nil {
+		return 0, err
+	}
+	builder.states[from].low = low
+	builder.states[from].high = high
+	builder.states[from].next = to
+	return to, nil
+}
+
+func (builder *nfaBuilder) addBytes(from int, target []byte) (int, error) {
+	var err error
+	for _, b := range target {
+		from, err = builder.addRange(from, b, b)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return from, nil
+}
+
+// addOptional adds the states of finder which can be skipped.
+// The epsilons are in the order in which the backtracking Finders try them, so
+// finder is tried first unless lazy is true.
+func (builder *nfaBuilder) addOptional(from int, finder Finder, lazy bool) (int, error) {
+	childStart, err := builder.newState()
+	if err != nil {
+		return 0, err
+	}
+	to, err := builder.newState()
+	if err != nil {
+		return 0, err
+	}
+	if lazy {
+		builder.addEpsilon(from, to)
+	}
+	builder.addEpsilon(from, childStart)
+	if !lazy {
+		builder.addEpsilon(from, to)
+	}
+	childEnd, err := builder.add(childStart, finder)
+	if err != nil {
+		return 0, err
+	}
+	builder.addIterationEpsilon(childEnd, to, childStart)
+	return to, nil
+}
+
+func (builder *nfaBuilder) addAlternatives(from int, childFinders []Finder) (int, error) {
+	to, err := builder.newState()
+	if err != nil {
+		return 0, err
+	}
+	for _, childFinder := range childFinders {
+		childStart, err := builder.newState()
+		if err != nil {
+			return 0, err
+		}
+		builder.addEpsilon(from, childStart)
+		childEnd, err := builder.add(childStart, childFinder)
+		if err != nil {
+			return 0, err
+		}
+		builder.addEpsilon(childEnd, to)
+	}
+	return to, nil
+}
+
+func (builder *nfaBuilder) addRepetition(from int, min int, max int, childFinder Finder, lazy bool) (int, error) {
+	var err error
+	for i := 0; i < min; i++ {
+		from, err = builder.add(from, childFinder)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if max >= 0 {
+		for i := min; i < max; i++ {
+			from, err = builder.addOptional(from, childFinder, lazy)
+			if err != nil {
+				return 0, err
+			}
+		}
+		return from, nil
+	}
+	loop, err := builder.newState()
+	if err != nil {
+		return 0, err
+	}
+	builder.addEpsilon(from, loop)
+	to := loop
+	if lazy {
+		// NOTE
+		// The following states are added from the returned state after the
+		// child, so the lazy repetition needs another state to leave the loop
+		// before it repeats.
+		to, err = builder.newState()
+		if err != nil {
+			return 0, err
+		}
+		builder.addEpsilon(loop, to)
+	}
+	childStart, err := builder.newState()
+	if err != nil {
+		return 0, err
+	}
+	builder.addEpsilon(loop, childStart)
+	childEnd, err := builder.add(childStart, childFinder)
+	if err != nil {
+		return 0, err
+	}
+	builder.addIterationEpsilon(childEnd, loop, loop)
+	return to, nil
+}
+
+// add adds the states of finder from the state from, and returns the last state.
+// ActionFinders and CaptureFinders are not added, because a DFA can't tell
+// where they find the syntax.
+func (builder *nfaBuilder) add(from int, finder Finder) (int, error) {
+	switch f := finder.(type) {
+	case ByteFinder:
+		return builder.addRange(from, f.target, f.target)
+	case *ByteFinder:
+		return builder.addRange(from, f.target, f.target)
+	case BytesFinder:
+		if len(f.target) == 0 {
+			break
+		}
+		return builder.addBytes(from, f.target)
+	case *BytesFinder:
+		if len(f.target) == 0 {
+			break
+		}
+		return builder.addBytes(from, f.target)
+	case CaseInsensitiveBytesFinder:
+		if len(f.target) == 0 {
+			break
+		}
+		return builder.add(from, f.caseSensitiveFinder())
+	case *CaseInsensitiveBytesFinder:
+		if len(f.target) == 0 {
+			break
+		}
+		return builder.add(from, f.caseSensitiveFinder())
+	case CrLfFinder, *CrLfFinder:
+		return builder.addBytes(from, []byte("\r\n"))
+	case *ValueRangeAlternativesFinder:
+		if f.rangeStart > f.rangeEnd {
+			break
+		}
+		return builder.addRange(from, f.rangeStart, f.rangeEnd)
+	case *RuleFinder:
+		for _, rule := range builder.rules {
+			if rule == f.name {
+				return 0, fmt.Errorf("%w: rule %v is recursive", ErrNotRegular, f.name)
+			}
+		}
+		builder.rules = append(builder.rules, f.name)
+		defer func() { builder.rules = builder.rules[:len(builder.rules)-1] }()
+		return builder.add(from, f.newFinder())
+	case *ConcatenationFinder:
+		var err error
+		for _, childFinder := range f.childFinders {
+			from, err = builder.add(from, childFinder)
+			if err != nil {
+				return 0, err
+			}
+		}
+		return from, nil
+	case *AlternativesFinder:
+		return builder.addAlternatives(from, f.childFinders)
+	case *LongestMatchAlternativesFinder:
+		if builder.mode == FirstMatch {
+			break
+		}
+		return builder.addAlternatives(from, f.childFinders)
+	case *VariableRepetitionMinMaxFinder:
+		return builder.addRepetition(from, f.min, f.max, f.childFinder, false)
+	case *LazyRepetitionMinMaxFinder:
+		return builder.addRepetition(from, f.min, f.max, f.childFinder, true)
+	case *DFAFinder:
+		return builder.add(from, f.finder)
+	}
+	text, _ := newPrinter().format(finder)
+	return 0, fmt.Errorf("%w: %v can not be converted", ErrNotRegular, text)
+}
+
+// nfa is the NFA from start to end built by nfaBuilder.
+type nfa struct {
+	states []nfaState
+	start  int
+	end    int
+}
+
+// endNode is a node of the list of the ends found by nfaThreads.
+type endNode struct {
+	end  int
+	next *endNode
+}
+
+// nfaThread is a thread of the NFA in state, or the list of the ends from
+// first to last if state is negative.
+type nfaThread struct {
+	state int
+	first *endNode
+	last  *endNode
+}
+
+// appendEnds appends the ends of thread to threads, joining them with the
+// last ends of threads.
+func appendEnds(threads []nfaThread, thread nfaThread) []nfaThread {
+	if len(threads) == 0 || threads[len(threads)-1].state >= 0 {
+		return append(threads, thread)
+	}
+	last := &threads[len(threads)-1]
+	last.last.next = thread.first
+	last.last = thread.last
+	return threads
+}
+
+// nfaClosure is the states added by addClosure at position.
+// entered are the states on the path from the thread, and appended are the
+// states whose threads or ends are appended.
+type nfaClosure struct {
+	position int
+	added    map[int]bool
+	entered  map[int]int
+	appended map[int]bool
+}
+
+func newNfaClosure(position int) *nfaClosure {
+	return &nfaClosure{position: position, added: map[int]bool{}, entered: map[int]int{}, appended: map[int]bool{}}
+}
+
+// addClosure appends the threads reachable from state by epsilons to threads,
+// in the order of their priorities. A state leaves by its byte or finds the
+// end after all of its epsilons, because the loops of the greedy repetitions
+// try the child first.
+//
+// NOTE
+// The repetitions never repeat the empty data after their min, so the
+// epsilons which finish the iterations started on the path are not followed.
+// A state on the path is entered again by the other epsilons, e.g. the loop of
+// the inner repetition of *( *a ) in the next iteration of the outer one,
+// because it has the higher priority than the rest of the first one.
+func (automaton *nfa) addClosure(threads []nfaThread, state int, c *nfaClosure) []nfaThread {
+	if c.added[state] && c.entered[state] == 0 {
+		return threads
+	}
+	c.added[state] = true
+	c.entered[state]++
+	s := automaton.states[state]
+	for i, epsilon := range s.epsilons {
+		if start := s.iterations[i]; start >= 0 && c.entered[start] > 0 {
+			continue
+		}
+		threads = automaton.addClosure(threads, epsilon, c)
+	}
+	c.entered[state]--
+	if c.appended[state] {
+		return threads
+	}
+	c.appended[state] = true
+	if state == automaton.end {
+		node := &endNode{end: c.position}
+		return appendEnds(threads, nfaThread{state: -1, first: node, last: node})
+	}
+	if s.next >= 0 {
+		threads = append(threads, nfaThread{state: state})
+	}
+	return threads
+}
+
+// firstMatchEnds returns all the ends of the data found by the NFA in the
+// order in which the backtracking Finders find them.
+//
+// NOTE
+// The threads are kept in the order of their priorities, with the ends found
+// between them, so that the ends found by a thread come after the ends found
+// by the threads before it, like the backtracking.
+// Only the first of the threads in the same state at the same position is
+// kept, because the others find the same ends after it.
+func (automaton *nfa) firstMatchEnds(data []byte) []int {
+	threads := automaton.addClosure([]nfaThread{}, automaton.start, newNfaClosure(0))
+	for i := 0; i < len(data); i++ {
+		nexts := []nfaThread{}
+		c := newNfaClosure(i + 1)
+		alive := false
+		for _, thread := range threads {
+			if thread.state < 0 {
+				nexts = appendEnds(nexts, thread)
+				continue
+			}
+			alive = true
+			s := automaton.states[thread.state]
+			if data[i] >= s.low && data[i] <= s.high {
+				nexts = automaton.addClosure(nexts, s.next, c)
+			}
+		}
+		if !alive {
+			break
+		}
+		threads = nexts
+	}
+	ends := []int{}
+	for _, thread := range threads {
+		for node := thread.first; thread.state < 0 && node != nil; node = node.next {
+			ends = append(ends, node.end)
+		}
+	}
+	return ends
+}
+
+// dfa is a deterministic finite automaton. The state 0 is the start state.
+// transitions are -1 if no data is found after the transitions.
+type dfa struct {
+	transitions [][256]int
+	accepting   []bool
+}
+
+// closure adds the states reachable from states by epsilons.
+func (builder *nfaBuilder) closure(states []int) []int {
+	added := map[int]bool{}
+	stack := append([]int{}, states...)
+	for len(stack) > 0 {
+		state := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if added[state] {
+			continue
+		}
+		added[state] = true
+		stack = append(stack, builder.states[state].epsilons...)
+	}
+	closure := []int{}
+	for state := range added {
+		closure = append(closure, state)
+	}
+	sort.Ints(closure)
+	return closure
+}
+
+func stateSetKey(states []int) string {
+	key := strings.Builder{}
+	for _, state := range states {
+		fmt.Fprintf(&key, "%v,", state)
+	}
+	return key.String()
+}
+
+// newDfa builds the DFA of the NFA from start to end by the subset construction.
+func (builder *nfaBuilder) newDfa(start int, end int) (*dfa, error) {
+	automaton := &dfa{}
+	sets := [][]int{}
+	ids := map[string]int{}
+	addSet := func(set []int) (int, error) {
+		key := stateSetKey(set)
+		if id, ok := ids[key]; ok {
+			return id, nil
+		}
+		if len(sets) >= maxDfaStates {
+			return 0, fmt.Errorf("%w: DFA has more than %v states", ErrNotRegular, maxDfaStates)
+		}
+		ids[key] = len(sets)
+		sets = append(sets, set)
+		accepting := false
+		for _, state := range set {
+			accepting = accepting || state == end
+		}
+		automaton.transitions = append(automaton.transitions, [256]int{})
+		automaton.accepting = append(automaton.accepting, accepting)
+		return len(sets) - 1, nil
+	}
+	if _, err := addSet(builder.closure([]int{start})); err != nil {
+		return nil, err
+	}
+	for id := 0; id < len(sets); id++ {
+		for b := 0; b < 256; b++ {
+			nexts := []int{}
+			for _, state := range sets[id] {
+				s := builder.states[state]
+				if s.next >= 0 && byte(b) >= s.low && byte(b) <= s.high {
+					nexts = append(nexts, s.next)
+				}
+			}
+			if len(nexts) == 0 {
+				automaton.transitions[id][b] = -1
+				continue
+			}
+			next, err := addSet(builder.closure(nexts))
+			if err != nil {
+				return nil, err
+			}
+			automaton.transitions[id][b] = next
+		}
+	}
+	automaton.removeDeadStates()
+	return automaton, nil
+}
+
+// removeDeadStates replaces the transitions to the states which never reach
+// the accepting states with -1, so that Find stops at them.
+func (automaton *dfa) removeDeadStates() {
+	alive := append([]bool{}, automaton.accepting...)
+	for changed := true; changed; {
+		changed = false
+		for id, transitions := range automaton.transitions {
+			if alive[id] {
+				continue
+			}
+			for _, next := range transitions {
+				if next >= 0 && alive[next] {
+					alive[id] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	for id := range automaton.transitions {
+		for b, next := range automaton.transitions[id] {
+			if next >= 0 && !alive[next] {
+				automaton.transitions[id][b] = -1
+			}
+		}
+	}
+}
+
+// ends returns all the ends of the data found by the DFA in ascending order.
+func (automaton *dfa) ends(data []byte) []int {
+	ends := []int{}
+	state := 0
+	if automaton.accepting[state] {
+		ends = append(ends, 0)
+	}
+	for i, b := range data {
+		state = automaton.transitions[state][b]
+		if state < 0 {
+			break
+		}
+		if automaton.accepting[state] {
+			ends = append(ends, i+1)
+		}
+	}
+	return ends
+}
+
+// DFAFinder finds the syntax of a Finder without recursive rules with a DFA,
+// in time linear in the data. With FirstMatch, it finds the syntax with the
+// NFA instead.
+// Find returns the data chosen by its MatchMode, and Recalculate returns the
+// other data in the same order, so DFAFinder can be used with the other Finders.
+type DFAFinder struct {
+	dfa    *dfa
+	nfa    *nfa
+	mode   MatchMode
+	ends   []int
+	finder Finder
+}
+
+func (finder *DFAFinder) Find(data []byte) (found bool, end int) {
+	if finder.mode == FirstMatch {
+		finder.ends = finder.nfa.firstMatchEnds(data)
+		return finder.Recalculate(data)
+	}
+	finder.ends = finder.dfa.ends(data)
+	if finder.mode == LongestMatch {
+		for i, j := 0, len(finder.ends)-1; i < j; i, j = i+1, j-1 {
+			finder.ends[i], finder.ends[j] = finder.ends[j], finder.ends[i]
+		}
+	}
+	return finder.Recalculate(data)
+}
+
+func (finder *DFAFinder) Copy() Finder {
+	return &DFAFinder{dfa: finder.dfa, nfa: finder.nfa, mode: finder.mode, finder: finder.finder}
+}
+
+func (finder *DFAFinder) Recalculate(data []byte) (found bool, end int) {
+	if len(finder.ends) == 0 {
+		return false, 0
+	}
+	end = finder.ends[0]
+	finder.ends = finder.ends[1:]
+	return true, end
+}
+
+// DFAFinder is rendered as the Finder it was built from.
+func (finder DFAFinder) formatABNF(p *printer) (string, int) {
+	return p.format(finder.finder)
+}
+
+func (finder DFAFinder) String() string {
+	text, _ := finder.formatABNF(newPrinter())
+	return text
+}
+
+// NewDFAFinder returns the DFAFinder of finder.
+// It returns an error wrapping ErrNotRegular if finder has a recursive rule,
+// an ActionFinder, a CaptureFinder or a Finder defined outside of this package,
+// a LongestMatchAlternativesFinder with FirstMatch, or if the DFA is too large.
+func NewDFAFinder(finder Finder, mode MatchMode) (*DFAFinder, error) {
+	builder := &nfaBuilder{rules: []string{}, mode: mode}
+	start, err := builder.newState()
+	if err != nil {
+		return nil, err
+	}
+	end, err := builder.add(start, finder)
+	if err != nil {
+		return nil, err
+	}
+	if mode == FirstMatch {
+		automaton := &nfa{states: builder.states, start: start, end: end}
+		return &DFAFinder{nfa: automaton, mode: mode, finder: finder.Copy()}, nil
+	}
+	automaton, err := builder.newDfa(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &DFAFinder{dfa: automaton, mode: mode, finder: finder.Copy()}, nil
+}
+
+// dfaCompiler replaces the parts of Finders with DFAFinders.
+// rules are the compiled rules, so that the recursive rules are compiled once.
+type dfaCompiler struct {
+	mode  MatchMode
+	mutex sync.Mutex
+	rules map[string]*RuleFinder
+}
+
+func (compiler *dfaCompiler) compileAll(finders []Finder) []Finder {
+	compiled := []Finder{}
+	for _, finder := range finders {
+		compiled = append(compiled, compiler.compile(finder))
+	}
+	return compiled
+}
+
+func (compiler *dfaCompiler) compile(finder Finder) Finder {
+	if dfaFinder, err := NewDFAFinder(finder, compiler.mode); err == nil {
+		return dfaFinder
+	}
+	switch f := finder.(type) {
+	case *RuleFinder:
+		compiler.mutex.Lock()
+		defer compiler.mutex.Unlock()
+		if rule, ok := compiler.rules[f.name]; ok {
+			return rule
+		}
+		// NOTE
+		// The definition is compiled when the rule is found first, because
+		// it refers the rule itself if the rule is recursive.
+		var once sync.Once
+		var definition Finder
+		rule := NewRuleFinder(f.name, func() Finder {
+			once.Do(func() { definition = compiler.compile(f.newFinder()) })
+			return definition.Copy()
+		})
+		compiler.rules[f.name] = rule
+		return rule
+	case *ConcatenationFinder:
+		return NewConcatenationFinder(compiler.compileAll(f.childFinders))
+	case *AlternativesFinder:
+		return NewAlternativesFinder(compiler.compileAll(f.childFinders))
+	case *LongestMatchAlternativesFinder:
+		return NewLongestMatchAlternativesFinder(compiler.compileAll(f.childFinders))
+	case *VariableRepetitionMinMaxFinder:
+		return NewVariableRepetitionMinMaxFinder(f.min, f.max, compiler.compile(f.childFinder))
+	case *LazyRepetitionMinMaxFinder:
+		return NewLazyRepetitionMinMaxFinder(f.min, f.max, compiler.compile(f.childFinder))
+	case *ActionFinder:
+		return NewActionFinder(compiler.compile(f.childFinder), f.action)
+	case *CaptureFinder:
+		return NewCaptureFinder(f.name, compiler.compile(f.childFinder))
+	case *PossessiveFinder:
+		return NewPossessiveFinder(compiler.compile(f.childFinder))
+	}
+	return finder.Copy()
+}
+
+// CompileDFA returns the Finder which finds the syntax of finder with
+// DFAFinders where possible. The largest parts of finder without recursive
+// rules, ActionFinders, CaptureFinders and Finders defined outside of this
+// package are replaced with DFAFinders, and the other parts remain the
+// backtracking Finders.
+//
+// The compiled Finder finds the same data as finder, but it might find them in
+// another order, so Parse might return another data. e.g. "a" / "ab" finds "ab"
+// from "ab" with LongestMatch, and *ALPHA finds "" from "abc" with
+// ShortestMatch. Use WithLongestMatch with finder for the same data as
+// LongestMatch, or FirstMatch for the same order as finder.
+//
+// NOTE
+// The rules replaced with DFAFinders are not reported to Tracers.
+func CompileDFA(finder Finder, mode MatchMode) Finder {
+	compiler := &dfaCompiler{mode: mode, rules: map[string]*RuleFinder{}}
+	return compiler.compile(finder)
+}
@@ -0,0 +1,360 @@
+package abnfp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDFAFinder(t *testing.T) {
+	type TestCase struct {
+		testName     string
+		data         []byte
+		finder       Finder
+		mode         MatchMode
+		expectedEnds []int
+	}
+
+	// *( a / ab ) b
+	ambiguous := NewConcatenationFinder([]Finder{
+		NewVariableRepetitionFinder(NewAlternativesFinder([]Finder{
+			NewByteFinder('a'),
+			NewBytesFinder([]byte("ab")),
+		})),
+		NewByteFinder('b'),
+	})
+
+	tests := []TestCase{
+		{
+			testName:     "data: []byte(\"abab\"), find *( a / ab ) b with LongestMatch",
+			data:         []byte("abab"),
+			finder:       ambiguous,
+			mode:         LongestMatch,
+			expectedEnds: []int{4, 2},
+		},
+		{
+			testName:     "data: []byte(\"abab\"), find *( a / ab ) b with ShortestMatch",
+			data:         []byte("abab"),
+			finder:       ambiguous,
+			mode:         ShortestMatch,
+			expectedEnds: []int{2, 4},
+		},
+		{
+			testName:     "data: []byte(\"abab\"), find *( a / ab ) b with FirstMatch",
+			data:         []byte("abab"),
+			finder:       ambiguous,
+			mode:         FirstMatch,
+			expectedEnds: []int{2, 4},
+		},
+		{
+			testName: "data: []byte(\"aaa\"), find *a a with lazy repetition and FirstMatch",
+			data:     []byte("aaa"),
+			finder: NewConcatenationFinder([]Finder{
+				NewLazyRepetitionFinder(NewByteFinder('a')),
+				NewByteFinder('a'),
+			}),
+			mode:         FirstMatch,
+			expectedEnds: []int{1, 2, 3},
+		},
+		{
+			testName:     "data: []byte(\"gEt\"), find \"GET\"",
+			data:         []byte("gEt"),
+			finder:       NewCaseInsensitiveBytesFinder([]byte("GET")),
+			mode:         LongestMatch,
+			expectedEnds: []int{3},
+		},
+		{
+			testName:     "data: []byte(\"c\"), find *( a / ab ) b",
+			data:         []byte("c"),
+			finder:       ambiguous,
+			mode:         LongestMatch,
+			expectedEnds: []int{},
+		},
+		{
+			testName:     "data: []byte(\"\\xFF\\xFEa\"), find *%x80-FF",
+			data:         []byte("\xFF\xFEa"),
+			finder:       NewVariableRepetitionFinder(NewValueRangeAlternativesFinder(0x80, 0xFF)),
+			mode:         LongestMatch,
+			expectedEnds: []int{2, 1, 0},
+		},
+		{
+			testName:     "data: []byte(\"aaaa\"), find 1*3a",
+			data:         []byte("aaaa"),
+			finder:       NewVariableRepetitionMinMaxFinder(1, 3, NewByteFinder('a')),
+			mode:         LongestMatch,
+			expectedEnds: []int{3, 2, 1},
+		},
+		{
+			testName:     "data: []byte(\"\\r\\n\"), find CRLF",
+			data:         []byte("\r\n"),
+			finder:       NewRuleFinder("line-end", func() Finder { return NewCrLfFinder() }),
+			mode:         ShortestMatch,
+			expectedEnds: []int{2},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			finder, err := NewDFAFinder(testCase.finder, testCase.mode)
+			equals(testCase.testName, t, nil, err)
+			ends := []int{}
+			found, end := finder.Find(testCase.data)
+			for found {
+				ends = append(ends, end)
+				found, end = finder.Recalculate(testCase.data)
+			}
+			sliceEquals(testCase.testName, t, testCase.expectedEnds, ends)
+		})
+	}
+}
+
+func TestNewDFAFinderError(t *testing.T) {
+	var nest *RuleFinder
+	nest = NewRuleFinder("nest", func() Finder {
+		return NewOptionalSequenceFinder(NewConcatenationFinder([]Finder{NewByteFinder('('), nest, NewByteFinder(')')}))
+	})
+	_, err := NewDFAFinder(nest, LongestMatch)
+	equals("recursive rule", t, true, errors.Is(err, ErrNotRegular))
+	_, err = NewDFAFinder(NewCaptureFinder("digit", NewDigitFinder()), LongestMatch)
+	equals("CaptureFinder", t, true, errors.Is(err, ErrNotRegular))
+	// *( a / b ) a 20( a / b )
+	_, err = NewDFAFinder(NewConcatenationFinder([]Finder{
+		NewVariableRepetitionFinder(NewValueRangeAlternativesFinder('a', 'b')),
+		NewByteFinder('a'),
+		NewSpecificRepetitionFinder(20, NewValueRangeAlternativesFinder('a', 'b')),
+	}), LongestMatch)
+	equals("too many states", t, true, errors.Is(err, ErrNotRegular))
+	_, err = NewDFAFinder(NewLongestMatchAlternativesFinder([]Finder{NewByteFinder('a'), NewBytesFinder([]byte("ab"))}), FirstMatch)
+	equals("LongestMatchAlternativesFinder with FirstMatch", t, true, errors.Is(err, ErrNotRegular))
+}
+
+func TestCompileDFA(t *testing.T) {
+	// list = item *( "," item )
+	// item = 1*DIGIT / "(" list ")"
+	var list *RuleFinder
+	item := NewRuleFinder("item", func() Finder {
+		return NewAlternativesFinder([]Finder{
+			NewVariableRepetitionMinFinder(1, NewDigitFinder()),
+			NewConcatenationFinder([]Finder{NewByteFinder('('), list, NewByteFinder(')')}),
+		})
+	})
+	list = NewRuleFinder("list", func() Finder {
+		return NewConcatenationFinder([]Finder{
+			item,
+			NewVariableRepetitionFinder(NewConcatenationFinder([]Finder{NewByteFinder(','), item})),
+		})
+	})
+
+	compiled := CompileDFA(list, LongestMatch)
+	equals("String", t, "list", fmt.Sprint(compiled))
+	for _, data := range []string{"1", "1,23", "(1,(2)),3", "((12)", "1,", ""} {
+		found, end := list.Copy().Find([]byte(data))
+		compiledFound, compiledEnd := compiled.Copy().Find([]byte(data))
+		equals(data, t, found, compiledFound)
+		equals(data, t, end, compiledEnd)
+		equals(data, t, findsAll(list, []byte(data)), findsAll(compiled, []byte(data)))
+	}
+
+	captures, _, err := ParseCaptures([]byte("12,3"), CompileDFA(NewConcatenationFinder([]Finder{
+		NewCaptureFinder("first", NewVariableRepetitionMinFinder(1, NewDigitFinder())),
+		NewVariableRepetitionFinder(NewConcatenationFinder([]Finder{NewByteFinder(','), NewDigitFinder()})),
+	}), LongestMatch))
+	equals("ParseCaptures", t, nil, err)
+	equals("ParseCaptures", t, 1, len(captures))
+	equals("ParseCaptures", t, "12", string(captures[0].Value))
+}
+
+// The DFAFinders are seen as the Finders they were built from.
+func TestDFAFinderSyntax(t *testing.T) {
+	// token = 1*( ALPHA / "-" )
+	token := NewRuleFinder("token", func() Finder {
+		return NewVariableRepetitionMinFinder(1, NewAlternativesFinder([]Finder{NewAlphaFinder(), NewByteFinder('-')}))
+	})
+	dfaFinder, err := NewDFAFinder(token, LongestMatch)
+	equals("NewDFAFinder", t, nil, err)
+
+	data, err := NewGenerator(1, 10, 3).Generate(dfaFinder)
+	equals("Generate", t, nil, err)
+	equals("Generate", t, true, findsAll(token, data))
+
+	expected := &bytes.Buffer{}
+	equals("WriteDot", t, nil, WriteDot(expected, token))
+	actual := &bytes.Buffer{}
+	equals("WriteDot", t, nil, WriteDot(actual, dfaFinder))
+	equals("WriteDot", t, expected.String(), actual.String())
+
+	pattern, err := RegexpString(dfaFinder)
+	equals("RegexpString", t, nil, err)
+	expectedPattern, _ := RegexpString(token)
+	equals("RegexpString", t, expectedPattern, pattern)
+
+	compiled := CompileDFA(NewConcatenationFinder([]Finder{
+		NewProseValFinder("scheme"),
+		NewByteFinder(':'),
+		token,
+	}), LongestMatch)
+	err = CheckProseVals(compiled)
+	equals("CheckProseVals", t, "abnfp: unbound prose-val: <scheme>", fmt.Sprint(err))
+	err = CheckProseVals(compiled, WithProseVal("scheme", token))
+	equals("CheckProseVals", t, nil, err)
+
+	// A DFAFinder in a Finder is compiled again.
+	recompiled, err := NewDFAFinder(NewConcatenationFinder([]Finder{dfaFinder, NewDigitFinder()}), LongestMatch)
+	equals("NewDFAFinder", t, nil, err)
+	found, end := recompiled.Find([]byte("ab-c1"))
+	equals("Find", t, true, found)
+	equals("Find", t, 5, end)
+}
+
+// distinctEnds returns the distinct ends of the data found by finder in
+// ascending order.
+func distinctEnds(finder Finder, data []byte) []int {
+	distinct := map[int]bool{}
+	for _, end := range recalculatedEnds(finder, data) {
+		distinct[end] = true
+	}
+	ends := []int{}
+	for end := 0; end <= len(data); end++ {
+		if distinct[end] {
+			ends = append(ends, end)
+		}
+	}
+	return ends
+}
+
+// firstEnds returns the distinct ends of the data found by finder in the order
+// in which they are found first.
+func firstEnds(finder Finder, data []byte) []int {
+	found := map[int]bool{}
+	ends := []int{}
+	for _, end := range recalculatedEnds(finder, data) {
+		if !found[end] {
+			found[end] = true
+			ends = append(ends, end)
+		}
+	}
+	return ends
+}
+
+// The compiled Finders find the same data as the backtracking Finders, but in
+// the order of their MatchMode.
+func TestCompileDFADifferential(t *testing.T) {
+	type TestCase struct {
+		testName string
+		finder   Finder
+		data     []string
+	}
+
+	tests := []TestCase{
+		{
+			testName: "*ALPHA",
+			finder:   NewVariableRepetitionFinder(NewAlphaFinder()),
+			data:     []string{"abc1", "", "1"},
+		},
+		{
+			testName: "\"a\" / \"ab\"",
+			finder:   NewAlternativesFinder([]Finder{NewBytesFinder([]byte("a")), NewBytesFinder([]byte("ab"))}),
+			data:     []string{"ab", "a", "b"},
+		},
+		{
+			testName: "*( a / ab ) b",
+			finder: NewConcatenationFinder([]Finder{
+				NewVariableRepetitionFinder(NewAlternativesFinder([]Finder{
+					NewByteFinder('a'),
+					NewBytesFinder([]byte("ab")),
+				})),
+				NewByteFinder('b'),
+			}),
+			data: []string{"abab", "abb", "ba", "aa"},
+		},
+		{
+			testName: "*a a with lazy repetition",
+			finder: NewConcatenationFinder([]Finder{
+				NewLazyRepetitionFinder(NewByteFinder('a')),
+				NewByteFinder('a'),
+			}),
+			data: []string{"aaa", "b"},
+		},
+		{
+			testName: "1*2( a / ab ) *( b / ab ) with lazy repetition",
+			finder: NewConcatenationFinder([]Finder{
+				NewLazyRepetitionMinMaxFinder(1, 2, NewAlternativesFinder([]Finder{
+					NewByteFinder('a'),
+					NewBytesFinder([]byte("ab")),
+				})),
+				NewVariableRepetitionFinder(NewAlternativesFinder([]Finder{
+					NewByteFinder('b'),
+					NewBytesFinder([]byte("ab")),
+				})),
+			}),
+			data: []string{"ababab", "aab", "b"},
+		},
+		{
+			testName: "*( *a ) [ab]",
+			finder: NewConcatenationFinder([]Finder{
+				NewVariableRepetitionFinder(NewVariableRepetitionFinder(NewByteFinder('a'))),
+				NewOptionalSequenceFinder(NewBytesFinder([]byte("ab"))),
+			}),
+			data: []string{"aab", "b", ""},
+		},
+	}
+
+	for _, testCase := range tests {
+		for _, data := range testCase.data {
+			testName := fmt.Sprintf("%v, data: %q", testCase.testName, data)
+			t.Run(testName, func(t *testing.T) {
+				ends := distinctEnds(testCase.finder.Copy(), []byte(data))
+				compiled := CompileDFA(testCase.finder, FirstMatch)
+				sliceEquals(testName, t, firstEnds(testCase.finder.Copy(), []byte(data)), recalculatedEnds(compiled, []byte(data)))
+				for _, mode := range []MatchMode{LongestMatch, ShortestMatch} {
+					compiled := CompileDFA(testCase.finder, mode)
+					compiledEnds := recalculatedEnds(compiled, []byte(data))
+					sliceEquals(testName, t, ends, distinctEnds(compiled, []byte(data)))
+					equals(testName, t, len(ends), len(compiledEnds))
+					if len(ends) == 0 {
+						continue
+					}
+					expected := ends[0]
+					if mode == LongestMatch {
+						expected = ends[len(ends)-1]
+					}
+					equals(testName, t, expected, compiledEnds[0])
+				}
+			})
+		}
+	}
+
+	// The backtracking Finder finds the first alternative first.
+	alternatives := NewAlternativesFinder([]Finder{NewBytesFinder([]byte("a")), NewBytesFinder([]byte("ab"))})
+	parsed, _ := Parse([]byte("ab"), alternatives)
+	equals("Parse", t, "a", string(parsed))
+	parsed, _ = Parse([]byte("ab"), CompileDFA(alternatives, LongestMatch))
+	equals("Parse LongestMatch", t, "ab", string(parsed))
+	parsed, _ = Parse([]byte("ab"), CompileDFA(alternatives, FirstMatch))
+	equals("Parse FirstMatch", t, "a", string(parsed))
+	parsed, _ = Parse([]byte("ab"), alternatives, WithLongestMatch())
+	equals("Parse WithLongestMatch", t, "ab", string(parsed))
+	parsed, _ = Parse([]byte("abc1"), CompileDFA(NewVariableRepetitionFinder(NewAlphaFinder()), ShortestMatch))
+	equals("Parse ShortestMatch", t, "", string(parsed))
+}
+
+func TestDFAFinderLinearTime(t *testing.T) {
+	// *( a / ab / aab ) c is exponential for the backtracking Finders
+	// when the data doesn't end with c.
+	finder, err := NewDFAFinder(NewConcatenationFinder([]Finder{
+		NewVariableRepetitionFinder(NewAlternativesFinder([]Finder{
+			NewByteFinder('a'),
+			NewBytesFinder([]byte("ab")),
+			NewBytesFinder([]byte("aab")),
+		})),
+		NewByteFinder('c'),
+	}), LongestMatch)
+	equals("NewDFAFinder", t, nil, err)
+	data := bytes.Repeat([]byte("aab"), 100000)
+	found, _ := finder.Find(data)
+	equals("Find", t, false, found)
+	found, end := finder.Find(append(data, 'c'))
+	equals("Find", t, true, found)
+	equals("Find", t, len(data)+1, end)
+}
@@ -0,0 +1,100 @@
+package abnfp
+
+type syntaxKind int
+
+const (
+	syntaxTerminal syntaxKind = iota
+	syntaxRule
+	syntaxConcatenation
+	syntaxAlternatives
+	syntaxRepetition
+)
+
+// syntaxNode is the structure of a Finder used to draw diagrams and to
+// generate data.
+type syntaxNode struct {
+	kind syntaxKind
+	// label is the ABNF of the terminal, the name of the rule or the repeat of
+	// the repetition.
+	label string
+	// finder is the Finder of the terminal.
+	finder   Finder
+	min      int
+	max      int
+	children []*syntaxNode
+}
+
+// syntaxRuleDefinition is a rule and the structure of its definition.
+type syntaxRuleDefinition struct {
+	name       string
+	definition *syntaxNode
+}
+
+// newSyntaxDefinitions returns the structure of finder, and the definitions of
+// the rules referred by it in the order in which they are first referred.
+// If finder is a RuleFinder, its definition is the first one.
+func newSyntaxDefinitions(finder Finder) (root *syntaxNode, rules []syntaxRuleDefinition) {
+	p := newPrinter()
+	root = newSyntaxNode(p, finder)
+	rules = []syntaxRuleDefinition{}
+	for i := 0; i < len(p.rules); i++ {
+		rule := p.rules[i]
+		rules = append(rules, syntaxRuleDefinition{name: rule.name, definition: newSyntaxNode(p, rule.newFinder())})
+	}
+	return root, rules
+}
+
+func newSyntaxNode(p *printer, finder Finder) *syntaxNode {
+	switch f := finder.(type) {
+	case *RuleFinder:
+		p.addRule(f)
+		return &syntaxNode{kind: syntaxRule, label: f.name}
+	case *ConcatenationFinder:
+		if len(f.childFinders) == 1 {
+			return newSyntaxNode(p, f.childFinders[0])
+		}
+		node := &syntaxNode{kind: syntaxConcatenation}
+		for _, childFinder := range f.childFinders {
+			node.children = append(node.children, newSyntaxNode(p, childFinder))
+		}
+		return node
+	case *AlternativesFinder:
+		if len(f.childFinders) != 0 {
+			return newAlternativesSyntaxNode(p, f.childFinders)
+		}
+	case *LongestMatchAlternativesFinder:
+		if len(f.childFinders) != 0 {
+			return newAlternativesSyntaxNode(p, f.childFinders)
+		}
+	case *VariableRepetitionMinMaxFinder:
+		return newRepetitionSyntaxNode(p, f.min, f.max, f.childFinder)
+	case *LazyRepetitionMinMaxFinder:
+		return newRepetitionSyntaxNode(p, f.min, f.max, f.childFinder)
+	}
+	if child, ok := syntaxChild(finder); ok {
+		return newSyntaxNode(p, child)
+	}
+	text, _ := p.format(finder)
+	return &syntaxNode{kind: syntaxTerminal, label: text, finder: finder}
+}
+
+func newRepetitionSyntaxNode(p *printer, min int, max int, childFinder Finder) *syntaxNode {
+	return &syntaxNode{
+		kind:     syntaxRepetition,
+		label:    formatRepeat(min, max),
+		min:      min,
+		max:      max,
+		children: []*syntaxNode{newSyntaxNode(p, childFinder)},
+	}
+}
+
+func newAlternativesSyntaxNode(p *printer, childFinders []Finder) *syntaxNode {
+	if len(childFinders) == 1 {
+		return newSyntaxNode(p, childFinders[0])
+	}
+	node := &syntaxNode{kind: syntaxAlternatives}
+	for _, childFinder := range childFinders {
+		node.children = append(node.children, newSyntaxNode(p, childFinder))
+	}
+	return node
+}
@@ -0,0 +1,89 @@
+package abnfp
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// dotWriter renders syntaxNodes as the nodes and edges of a Graphviz graph.
+type dotWriter struct {
+	builder *strings.Builder
+	count   int
+	ruleIds map[string]string
+}
+
+func quoteDot(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "\"", "\\\"")
+	return "\"" + s + "\""
+}
+
+func (w *dotWriter) newId() string {
+	w.count++
+	return fmt.Sprintf("n%v", w.count)
+}
+
+// ruleId returns the id of the node of the rule. All references to the same
+// rule share the node.
+func (w *dotWriter) ruleId(name string) string {
+	id, ok := w.ruleIds[name]
+	if !ok {
+		id = w.newId()
+		w.ruleIds[name] = id
+		fmt.Fprintf(w.builder, "\t%v [label=%v, shape=box, style=bold];\n", id, quoteDot(name))
+	}
+	return id
+}
+
+// writeNode writes node and its children, and returns the id of node.
+func (w *dotWriter) writeNode(node *syntaxNode) string {
+	if node.kind == syntaxRule {
+		return w.ruleId(node.label)
+	}
+	id := w.newId()
+	switch node.kind {
+	case syntaxTerminal:
+		fmt.Fprintf(w.builder, "\t%v [label=%v, shape=box, style=rounded];\n", id, quoteDot(node.label))
+	case syntaxConcatenation:
+		fmt.Fprintf(w.builder, "\t%v [label=\"concatenation\", shape=ellipse];\n", id)
+	case syntaxAlternatives:
+		fmt.Fprintf(w.builder, "\t%v [label=\"alternatives\", shape=ellipse];\n", id)
+	case syntaxRepetition:
+		label := "repetition " + node.label
+		if node.min == 0 && node.max == 1 {
+			label = "optional"
+		}
+		fmt.Fprintf(w.builder, "\t%v [label=%v, shape=ellipse];\n", id, quoteDot(label))
+	}
+	for i, child := range node.children {
+		childId := w.writeNode(child)
+		if node.kind == syntaxConcatenation {
+			fmt.Fprintf(w.builder, "\t%v -> %v [label=\"%v\"];\n", id, childId, i+1)
+			continue
+		}
+		fmt.Fprintf(w.builder, "\t%v -> %v;\n", id, childId)
+	}
+	return id
+}
+
+// WriteDot writes the structure of finder to w as a Graphviz DOT graph.
+// The rules referred by finder are written once with the edges to their
+// definitions, so the recursive rules make cycles.
+func WriteDot(w io.Writer, finder Finder) error {
+	root, rules := newSyntaxDefinitions(finder)
+	dot := &dotWriter{builder: &strings.Builder{}, ruleIds: map[string]string{}}
+	dot.builder.WriteString("digraph abnf {\n")
+	dot.builder.WriteString("\tnode [fontname=\"monospace\"];\n")
+	if root.kind != syntaxRule {
+		dot.writeNode(root)
+	}
+	for _, rule := range rules {
+		ruleId := dot.ruleId(rule.name)
+		definitionId := dot.writeNode(rule.definition)
+		fmt.Fprintf(dot.builder, "\t%v -> %v;\n", ruleId, definitionId)
+	}
+	dot.builder.WriteString("}\n")
+	_, err := io.WriteString(w, dot.builder.String())
+	return err
+}
@@ -0,0 +1,92 @@
+package abnfp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteDot(t *testing.T) {
+	type TestCase struct {
+		testName string
+		finder   Finder
+		expected string
+	}
+
+	// list = item *( "," item )
+	// item = 1*DIGIT / "(" list ")"
+	var list *RuleFinder
+	item := NewRuleFinder("item", func() Finder {
+		return NewAlternativesFinder([]Finder{
+			NewVariableRepetitionMinFinder(1, NewDigitFinder()),
+			NewConcatenationFinder([]Finder{NewByteFinder('('), list, NewByteFinder(')')}),
+		})
+	})
+	list = NewRuleFinder("list", func() Finder {
+		return NewConcatenationFinder([]Finder{
+			item,
+			NewVariableRepetitionFinder(NewConcatenationFinder([]Finder{NewByteFinder(','), item})),
+		})
+	})
+
+	tests := []TestCase{
+		{
+			testName: "ByteFinder",
+			finder:   NewByteFinder('"'),
+			expected: "digraph abnf {\n" +
+				"\tnode [fontname=\"monospace\"];\n" +
+				"\tn1 [label=\"%x22\", shape=box, style=rounded];\n" +
+				"}\n",
+		},
+		{
+			testName: "OptionalSequenceFinder",
+			finder:   NewOptionalSequenceFinder(NewByteFinder('a')),
+			expected: "digraph abnf {\n" +
+				"\tnode [fontname=\"monospace\"];\n" +
+				"\tn1 [label=\"optional\", shape=ellipse];\n" +
+				"\tn2 [label=\"%x61\", shape=box, style=rounded];\n" +
+				"\tn1 -> n2;\n" +
+				"}\n",
+		},
+		{
+			testName: "recursive RuleFinders",
+			finder:   list,
+			expected: "digraph abnf {\n" +
+				"\tnode [fontname=\"monospace\"];\n" +
+				"\tn1 [label=\"list\", shape=box, style=bold];\n" +
+				"\tn2 [label=\"concatenation\", shape=ellipse];\n" +
+				"\tn3 [label=\"item\", shape=box, style=bold];\n" +
+				"\tn2 -> n3 [label=\"1\"];\n" +
+				"\tn4 [label=\"repetition *\", shape=ellipse];\n" +
+				"\tn5 [label=\"concatenation\", shape=ellipse];\n" +
+				"\tn6 [label=\"%x2C\", shape=box, style=rounded];\n" +
+				"\tn5 -> n6 [label=\"1\"];\n" +
+				"\tn5 -> n3 [label=\"2\"];\n" +
+				"\tn4 -> n5;\n" +
+				"\tn2 -> n4 [label=\"2\"];\n" +
+				"\tn1 -> n2;\n" +
+				"\tn7 [label=\"alternatives\", shape=ellipse];\n" +
+				"\tn8 [label=\"repetition 1*\", shape=ellipse];\n" +
+				"\tn9 [label=\"%x30-39\", shape=box, style=rounded];\n" +
+				"\tn8 -> n9;\n" +
+				"\tn7 -> n8;\n" +
+				"\tn10 [label=\"concatenation\", shape=ellipse];\n" +
+				"\tn11 [label=\"%x28\", shape=box, style=rounded];\n" +
+				"\tn10 -> n11 [label=\"1\"];\n" +
+				"\tn10 -> n1 [label=\"2\"];\n" +
+				"\tn12 [label=\"%x29\", shape=box, style=rounded];\n" +
+				"\tn10 -> n12 [label=\"3\"];\n" +
+				"\tn7 -> n10;\n" +
+				"\tn3 -> n7;\n" +
+				"}\n",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := WriteDot(buf, testCase.finder)
+			equals(testCase.testName, t, nil, err)
+			equals(testCase.testName, t, testCase.expected, buf.String())
+		})
+	}
+}
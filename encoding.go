@@ -0,0 +1,219 @@
+package abnfp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidTree is returned when the encoded tree can not be decoded.
+var ErrInvalidTree = errors.New("abnfp: invalid tree")
+
+// jsonNode is the JSON of Node.
+type jsonNode struct {
+	Rule     string      `json:"rule"`
+	Start    int         `json:"start"`
+	End      int         `json:"end"`
+	Text     string      `json:"text"`
+	Children []*jsonNode `json:"children"`
+}
+
+func newJSONNode(node *Node, data []byte) *jsonNode {
+	children := []*jsonNode{}
+	for _, child := range node.Children {
+		children = append(children, newJSONNode(child, data))
+	}
+	return &jsonNode{
+		Rule:     node.Name,
+		Start:    node.Start,
+		End:      node.End,
+		Text:     node.Text(data),
+		Children: children,
+	}
+}
+
+func (node *jsonNode) node() *Node {
+	decoded := &Node{Name: node.Rule, Span: Span{Start: node.Start, End: node.End}}
+	for _, child := range node.Children {
+		decoded.Children = append(decoded.Children, child.node())
+	}
+	return decoded
+}
+
+// EncodeJSON writes the tree of root found in data as the indented JSON like
+//
+//	{
+//	  "rule": "item",
+//	  "start": 0,
+//	  "end": 1,
+//	  "text": "1",
+//	  "children": []
+//	}
+//
+// The keys are always in this order, so the JSON is stable for the same tree.
+func EncodeJSON(w io.Writer, root *Node, data []byte) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(newJSONNode(root, data))
+}
+
+// DecodeJSON reads the tree written by EncodeJSON.
+// The texts are not decoded, because they are in the data.
+func DecodeJSON(r io.Reader) (*Node, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	node := &jsonNode{}
+	if err := decoder.Decode(node); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTree, err)
+	}
+	return node.node(), nil
+}
+
+// EncodeSExpr writes the tree of root found in data as the compact
+// S-expression like
+//
+//	(list 0 5 "1,(2)" (item 0 1 "1") (item 2 5 "(2)" (list 3 4 "2" (item 3 4 "2"))))
+//
+// Each Node is the list of its name, start, end, text and children.
+// The texts are quoted like Go strings, and the name of the root without name
+// is "".
+func EncodeSExpr(w io.Writer, root *Node, data []byte) error {
+	writer := bufio.NewWriter(w)
+	writeSExpr(writer, root, data)
+	writer.WriteString("\n")
+	return writer.Flush()
+}
+
+func writeSExpr(w *bufio.Writer, node *Node, data []byte) {
+	name := node.Name
+	if name == "" {
+		name = `""`
+	}
+	fmt.Fprintf(w, "(%v %v %v %v", name, node.Start, node.End, strconv.Quote(node.Text(data)))
+	for _, child := range node.Children {
+		w.WriteString(" ")
+		writeSExpr(w, child, data)
+	}
+	w.WriteString(")")
+}
+
+// DecodeSExpr reads the tree written by EncodeSExpr.
+// The texts are not decoded, because they are in the data.
+func DecodeSExpr(r io.Reader) (*Node, error) {
+	text, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	decoder := &sExprDecoder{text: string(text)}
+	node, err := decoder.node()
+	if err != nil {
+		return nil, err
+	}
+	decoder.skipSpaces()
+	if decoder.pos != len(decoder.text) {
+		return nil, decoder.errorf("unexpected %q", decoder.text[decoder.pos])
+	}
+	return node, nil
+}
+
+type sExprDecoder struct {
+	text string
+	pos  int
+}
+
+func (d *sExprDecoder) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: offset %v: %v", ErrInvalidTree, d.pos, fmt.Sprintf(format, args...))
+}
+
+func (d *sExprDecoder) skipSpaces() {
+	for d.pos < len(d.text) && strings.IndexByte(" \t\r\n", d.text[d.pos]) >= 0 {
+		d.pos++
+	}
+}
+
+// atom returns the next symbol, number or quoted string as it is written.
+func (d *sExprDecoder) atom() (string, error) {
+	d.skipSpaces()
+	start := d.pos
+	if d.pos < len(d.text) && d.text[d.pos] == '"' {
+		d.pos++
+		for d.pos < len(d.text) && d.text[d.pos] != '"' {
+			if d.text[d.pos] == '\\' {
+				d.pos++
+			}
+			d.pos++
+		}
+		if d.pos >= len(d.text) {
+			return "", d.errorf("unterminated string")
+		}
+		d.pos++
+		return d.text[start:d.pos], nil
+	}
+	for d.pos < len(d.text) && strings.IndexByte(" \t\r\n()\"", d.text[d.pos]) < 0 {
+		d.pos++
+	}
+	if start == d.pos {
+		return "", d.errorf("expected atom")
+	}
+	return d.text[start:d.pos], nil
+}
+
+func (d *sExprDecoder) number() (int, error) {
+	atom, err := d.atom()
+	if err != nil {
+		return 0, err
+	}
+	number, err := strconv.Atoi(atom)
+	if err != nil {
+		return 0, d.errorf("expected number, found %v", atom)
+	}
+	return number, nil
+}
+
+func (d *sExprDecoder) node() (*Node, error) {
+	d.skipSpaces()
+	if d.pos >= len(d.text) || d.text[d.pos] != '(' {
+		return nil, d.errorf("expected \"(\"")
+	}
+	d.pos++
+	name, err := d.atom()
+	if err != nil {
+		return nil, err
+	}
+	if name == `""` {
+		name = ""
+	}
+	node := &Node{Name: name}
+	if node.Start, err = d.number(); err != nil {
+		return nil, err
+	}
+	if node.End, err = d.number(); err != nil {
+		return nil, err
+	}
+	text, err := d.atom()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := strconv.Unquote(text); err != nil {
+		return nil, d.errorf("expected string, found %v", text)
+	}
+	for {
+		d.skipSpaces()
+		if d.pos >= len(d.text) {
+			return nil, d.errorf("expected \")\"")
+		}
+		if d.text[d.pos] == ')' {
+			d.pos++
+			return node, nil
+		}
+		child, err := d.node()
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+	}
+}
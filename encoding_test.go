@@ -0,0 +1,180 @@
+package abnfp
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	type TestCase struct {
+		testName      string
+		data          []byte
+		rule          string
+		expectedJSON  string
+		expectedSExpr string
+	}
+
+	g, err := CompileGrammar([]byte("list = item *( \",\" item )\n" +
+		"item = 1*ALPHA / DQUOTE *( %x20-21 / %x23-7E ) DQUOTE / \"(\" list \")\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []TestCase{
+		{
+			testName: "data: []byte(\"a\"), parse item",
+			data:     []byte("a"),
+			rule:     "item",
+			expectedJSON: "{\n" +
+				"  \"rule\": \"item\",\n" +
+				"  \"start\": 0,\n" +
+				"  \"end\": 1,\n" +
+				"  \"text\": \"a\",\n" +
+				"  \"children\": []\n" +
+				"}\n",
+			expectedSExpr: "(item 0 1 \"a\")\n",
+		},
+		{
+			testName: "data: []byte(\"\\\"b c\\\",(a)\"), parse list",
+			data:     []byte("\"b c\",(a)"),
+			rule:     "list",
+			expectedJSON: "{\n" +
+				"  \"rule\": \"list\",\n" +
+				"  \"start\": 0,\n" +
+				"  \"end\": 9,\n" +
+				"  \"text\": \"\\\"b c\\\",(a)\",\n" +
+				"  \"children\": [\n" +
+				"    {\n" +
+				"      \"rule\": \"item\",\n" +
+				"      \"start\": 0,\n" +
+				"      \"end\": 5,\n" +
+				"      \"text\": \"\\\"b c\\\"\",\n" +
+				"      \"children\": []\n" +
+				"    },\n" +
+				"    {\n" +
+				"      \"rule\": \"item\",\n" +
+				"      \"start\": 6,\n" +
+				"      \"end\": 9,\n" +
+				"      \"text\": \"(a)\",\n" +
+				"      \"children\": [\n" +
+				"        {\n" +
+				"          \"rule\": \"list\",\n" +
+				"          \"start\": 7,\n" +
+				"          \"end\": 8,\n" +
+				"          \"text\": \"a\",\n" +
+				"          \"children\": [\n" +
+				"            {\n" +
+				"              \"rule\": \"item\",\n" +
+				"              \"start\": 7,\n" +
+				"              \"end\": 8,\n" +
+				"              \"text\": \"a\",\n" +
+				"              \"children\": []\n" +
+				"            }\n" +
+				"          ]\n" +
+				"        }\n" +
+				"      ]\n" +
+				"    }\n" +
+				"  ]\n" +
+				"}\n",
+			expectedSExpr: "(list 0 9 \"\\\"b c\\\",(a)\" (item 0 5 \"\\\"b c\\\"\") (item 6 9 \"(a)\" (list 7 8 \"a\" (item 7 8 \"a\"))))\n",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			root, _, err := ParseTree(testCase.data, g.Rule(testCase.rule))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			encoded := &bytes.Buffer{}
+			equals(testCase.testName, t, nil, EncodeJSON(encoded, root, testCase.data))
+			equals(testCase.testName, t, testCase.expectedJSON, encoded.String())
+			decoded, err := DecodeJSON(encoded)
+			equals(testCase.testName, t, nil, err)
+			equals(testCase.testName, t, formatNode(root), formatNode(decoded))
+
+			encoded = &bytes.Buffer{}
+			equals(testCase.testName, t, nil, EncodeSExpr(encoded, root, testCase.data))
+			equals(testCase.testName, t, testCase.expectedSExpr, encoded.String())
+			decoded, err = DecodeSExpr(encoded)
+			equals(testCase.testName, t, nil, err)
+			equals(testCase.testName, t, formatNode(root), formatNode(decoded))
+		})
+	}
+}
+
+func TestEncodeSExprRootWithoutName(t *testing.T) {
+	data := []byte("ab")
+	root := &Node{Span: Span{Start: 0, End: 2}, Children: []*Node{{Name: "a", Span: Span{Start: 0, End: 1}}}}
+	encoded := &bytes.Buffer{}
+	equals("EncodeSExpr", t, nil, EncodeSExpr(encoded, root, data))
+	equals("EncodeSExpr", t, "(\"\" 0 2 \"ab\" (a 0 1 \"a\"))\n", encoded.String())
+	decoded, err := DecodeSExpr(encoded)
+	equals("DecodeSExpr", t, nil, err)
+	equals("DecodeSExpr", t, formatNode(root), formatNode(decoded))
+}
+
+func TestDecodeError(t *testing.T) {
+	type TestCase struct {
+		testName    string
+		decode      func(encoded string) (*Node, error)
+		encoded     string
+		expectedErr string
+	}
+
+	decodeJSON := func(encoded string) (*Node, error) { return DecodeJSON(strings.NewReader(encoded)) }
+	decodeSExpr := func(encoded string) (*Node, error) { return DecodeSExpr(strings.NewReader(encoded)) }
+
+	tests := []TestCase{
+		{
+			testName:    "JSON with unknown key",
+			decode:      decodeJSON,
+			encoded:     "{\"name\": \"a\"}",
+			expectedErr: "abnfp: invalid tree: json: unknown field \"name\"",
+		},
+		{
+			testName:    "JSON not closed",
+			decode:      decodeJSON,
+			encoded:     "{\"rule\": \"a\"",
+			expectedErr: "abnfp: invalid tree: unexpected EOF",
+		},
+		{
+			testName:    "S-expression without number",
+			decode:      decodeSExpr,
+			encoded:     "(a 0 b \"a\")",
+			expectedErr: "abnfp: invalid tree: offset 6: expected number, found b",
+		},
+		{
+			testName:    "S-expression without text",
+			decode:      decodeSExpr,
+			encoded:     "(a 0 1 a)",
+			expectedErr: "abnfp: invalid tree: offset 8: expected string, found a",
+		},
+		{
+			testName:    "S-expression not closed",
+			decode:      decodeSExpr,
+			encoded:     "(a 0 1 \"a\" (b 0 1 \"a\")",
+			expectedErr: "abnfp: invalid tree: offset 22: expected \")\"",
+		},
+		{
+			testName:    "S-expression with trailing list",
+			decode:      decodeSExpr,
+			encoded:     "(a 0 1 \"a\") (b 0 1 \"a\")",
+			expectedErr: "abnfp: invalid tree: offset 12: unexpected '('",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			node, err := testCase.decode(testCase.encoded)
+			if err == nil {
+				t.Fatalf("%v: expected err: %v, actual: %v", testCase.testName, testCase.expectedErr, formatNode(node))
+			}
+			equals(testCase.testName, t, true, errors.Is(err, ErrInvalidTree))
+			equals(testCase.testName, t, testCase.expectedErr, err.Error())
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package abnfp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+// grammarBuilder builds a Finder and the equivalent regular expression from
+// fuzzed data, so that the fuzzer explores the combinations of Finders.
+type grammarBuilder struct {
+	data []byte
+}
+
+// The maximum depth of the Finders built by grammarBuilder.
+const maxBuildDepth = 3
+
+// The maximum steps of the parse which finds all the data the fuzzed Finder
+// can find. The inputs which take more steps are skipped.
+const maxFuzzSteps = 100000
+
+func (builder *grammarBuilder) next() int {
+	if len(builder.data) == 0 {
+		return 0
+	}
+	b := builder.data[0]
+	builder.data = builder.data[1:]
+	return int(b)
+}
+
+func (builder *grammarBuilder) children(depth int) ([]Finder, []string) {
+	count := 2 + builder.next()%2
+	finders, patterns := []Finder{}, []string{}
+	for i := 0; i < count; i++ {
+		finder, pattern := builder.build(depth + 1)
+		finders = append(finders, finder)
+		patterns = append(patterns, "(?:"+pattern+")")
+	}
+	return finders, patterns
+}
+
+// build returns a Finder and the regular expression which matches the same
+// language. The terminals are "a", "b" and "c".
+func (builder *grammarBuilder) build(depth int) (Finder, string) {
+	op := builder.next()
+	if depth >= maxBuildDepth {
+		op %= 3
+	}
+	switch op % 9 {
+	case 0:
+		c := byte('a' + builder.next()%3)
+		return NewByteFinder(c), string(c)
+	case 1:
+		return NewBytesFinder([]byte("ab")), "ab"
+	case 2:
+		return NewValueRangeAlternativesFinder('a', 'b'), "[a-b]"
+	case 3:
+		finders, patterns := builder.children(depth)
+		pattern := ""
+		for _, p := range patterns {
+			pattern += p
+		}
+		return NewConcatenationFinder(finders), pattern
+	case 4:
+		finders, patterns := builder.children(depth)
+		pattern := patterns[0]
+		for _, p := range patterns[1:] {
+			pattern += "|" + p
+		}
+		return NewAlternativesFinder(finders), pattern
+	case 5:
+		min := builder.next() % 3
+		max := min + builder.next()%3
+		if builder.next()%2 == 0 {
+			max = -1
+		}
+		finder, pattern := builder.build(depth + 1)
+		if max < 0 {
+			return NewVariableRepetitionMinFinder(min, finder), fmt.Sprintf("(?:%v){%v,}", pattern, min)
+		}
+		return NewVariableRepetitionMinMaxFinder(min, max, finder), fmt.Sprintf("(?:%v){%v,%v}", pattern, min, max)
+	case 6:
+		finder, pattern := builder.build(depth + 1)
+		return NewOptionalSequenceFinder(finder), "(?:" + pattern + ")?"
+	case 7:
+		finder, pattern := builder.build(depth + 1)
+		return NewVariableRepetitionFinder(finder), "(?:" + pattern + ")*"
+	default:
+		min := builder.next() % 3
+		finder, pattern := builder.build(depth + 1)
+		return NewLazyRepetitionMinFinder(min, finder), fmt.Sprintf("(?:%v){%v,}?", pattern, min)
+	}
+}
+
+func addFuzzSeeds(f *testing.F) {
+	seeds := []struct {
+		grammar []byte
+		input   string
+	}{
+		// *a a
+		{[]byte{3, 0, 7, 0, 0, 0, 0}, "aaa"},
+		// ( a / ab ) b
+		{[]byte{3, 0, 4, 0, 0, 0, 1, 0, 0, 1}, "abb"},
+		// 1*2( a / ab ) b
+		{[]byte{3, 0, 5, 1, 1, 1, 4, 0, 0, 0, 1, 0, 1}, "aabab"},
+		// *( [a] b ) [a-b]
+		{[]byte{3, 0, 7, 3, 0, 6, 0, 0, 0, 1, 2}, "abbab"},
+		// *( *a ) b
+		{[]byte{3, 0, 7, 7, 0, 0, 0, 1}, "aab"},
+	}
+	for _, seed := range seeds {
+		f.Add(seed.grammar, []byte(seed.input))
+	}
+}
+
+// FuzzFinder checks the invariants of the Finders and compares them with the
+// equivalent regular expressions.
+func FuzzFinder(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, grammar []byte, input []byte) {
+		if len(input) > 16 {
+			// The backtracking of the ambiguous grammars is exponential in the input.
+			return
+		}
+		builder := &grammarBuilder{data: grammar}
+		finder, pattern := builder.build(0)
+
+		// NOTE
+		// The Finders try the choices of the ambiguous grammars, which might be
+		// exponential in the input. e.g. 2*( 2*[ %x61-62 ] )
+		// WithLongestMatch tries all of them, so the checks below take at most
+		// as many steps as it.
+		if _, _, err := ParseContext(context.Background(), input, finder, WithLongestMatch(), WithMaxSteps(maxFuzzSteps)); errors.Is(err, ErrAborted) {
+			t.Skipf("%v: %v, input: %q", finder, err, input)
+		}
+
+		found, end := finder.Copy().Find(input)
+		if end < 0 || end > len(input) || (!found && end != 0) {
+			t.Fatalf("%v: invalid result found: %v, end: %v, input: %q", finder, found, end, input)
+		}
+
+		// Find is deterministic.
+		otherFound, otherEnd := finder.Copy().Find(input)
+		if found != otherFound || end != otherEnd {
+			t.Fatalf("%v: Find is not deterministic, input: %q", finder, input)
+		}
+
+		// The Copy of the Finder which has found the syntax finds the same one.
+		used := finder.Copy()
+		used.Find(input)
+		copyFound, copyEnd := used.Copy().Find(input)
+		if found != copyFound || end != copyEnd {
+			t.Fatalf("%v: Copy finds a different syntax, input: %q", finder, input)
+		}
+
+		// Recalculate finds other data within the input.
+		if variableFinder, ok := used.(VariableFinder); ok && found {
+			for i := 0; i < 8; i++ {
+				otherFound, otherEnd := variableFinder.Recalculate(input)
+				if !otherFound {
+					break
+				}
+				if otherEnd < 0 || otherEnd > len(input) {
+					t.Fatalf("%v: invalid recalculated end: %v, input: %q", finder, otherEnd, input)
+				}
+			}
+		}
+
+		parsed, remaining := Parse(input, finder)
+		if !bytes.Equal(append(append([]byte{}, parsed...), remaining...), input) {
+			t.Fatalf("%v: parsed %q and remaining %q are not the input %q", finder, parsed, remaining, input)
+		}
+
+		// The Finder finds all of the input if and only if the regular
+		// expression matches it.
+		re := regexp.MustCompile("^(?:" + pattern + ")$")
+		if findsAll(finder, input) != re.Match(input) {
+			t.Fatalf("%v: finds all: %v, but %v matches: %v, input: %q",
+				finder, findsAll(finder, input), re, re.Match(input), input)
+		}
+
+		// The DFAFinders find all of the input if and only if the Finder does,
+		// and they find the same data as the Finder.
+		ends := distinctEnds(finder.Copy(), input)
+		for _, mode := range []MatchMode{LongestMatch, ShortestMatch} {
+			dfaFinder, err := NewDFAFinder(finder, mode)
+			if err != nil {
+				t.Fatalf("%v: %v", finder, err)
+			}
+			if findsAll(dfaFinder, input) != re.Match(input) {
+				t.Fatalf("%v: DFAFinder finds all: %v, but %v matches: %v, input: %q",
+					finder, findsAll(dfaFinder, input), re, re.Match(input), input)
+			}
+			if dfaEnds := distinctEnds(dfaFinder, input); fmt.Sprint(dfaEnds) != fmt.Sprint(ends) {
+				t.Fatalf("%v: DFAFinder finds %v, but the Finder finds %v, input: %q", finder, dfaEnds, ends, input)
+			}
+		}
+
+		// The DFAFinder with FirstMatch finds the data in the same order as the Finder.
+		dfaFinder, err := NewDFAFinder(finder, FirstMatch)
+		if err != nil {
+			t.Fatalf("%v: %v", finder, err)
+		}
+		if dfaEnds, ends := recalculatedEnds(dfaFinder, input), firstEnds(finder.Copy(), input); fmt.Sprint(dfaEnds) != fmt.Sprint(ends) {
+			t.Fatalf("%v: DFAFinder with FirstMatch finds %v, but the Finder finds %v, input: %q", finder, dfaEnds, ends, input)
+		}
+
+		// RegexpString converts the Finder to the regular expression of the same language.
+		convertedPattern, err := RegexpString(finder)
+		if err != nil {
+			t.Fatalf("%v: %v", finder, err)
+		}
+		converted := regexp.MustCompile("^(?:" + convertedPattern + ")$")
+		if converted.Match(input) != re.Match(input) {
+			t.Fatalf("%v: %v matches: %v, but %v matches: %v, input: %q",
+				finder, converted, converted.Match(input), re, re.Match(input), input)
+		}
+	})
+}
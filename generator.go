@@ -0,0 +1,192 @@
+package abnfp
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// unreachable is the height of the syntax which never finishes.
+const unreachable = int(^uint(0) >> 2)
+
+// Generator generates random data found by Finders.
+type Generator struct {
+	rand      *rand.Rand
+	maxDepth  int
+	maxRepeat int
+}
+
+// generation is the state of a Generate call.
+type generation struct {
+	generator *Generator
+	rules     map[string]*syntaxNode
+	// heights are the minimum depths needed to finish the rules.
+	heights map[string]int
+	data    []byte
+}
+
+// Generate returns random data found by finder.
+// Alternatives are chosen at random, and repetitions repeat a random number of
+// times between their min and max. An unlimited repetition repeats at most
+// maxRepeat times more than its min.
+// Deeper than maxDepth, the generator takes the shortest way to finish the
+// data, so recursive rules end.
+// Generate returns an error if finder has a Finder defined outside of this
+// package, or finder never finishes.
+func (generator *Generator) Generate(finder Finder) ([]byte, error) {
+	root, rules := newSyntaxDefinitions(finder)
+	g := &generation{
+		generator: generator,
+		rules:     map[string]*syntaxNode{},
+		heights:   map[string]int{},
+		data:      []byte{},
+	}
+	for _, rule := range rules {
+		g.rules[rule.name] = rule.definition
+		g.heights[rule.name] = unreachable
+	}
+	// NOTE
+	// The height of a recursive rule depends on itself.
+	// Update the heights until all of them are fixed.
+	for changed := true; changed; {
+		changed = false
+		for _, rule := range rules {
+			height := g.height(rule.definition) + 1
+			if height < g.heights[rule.name] {
+				g.heights[rule.name] = height
+				changed = true
+			}
+		}
+	}
+	if g.height(root) >= unreachable {
+		return nil, fmt.Errorf("abnfp: %v never finishes", fmt.Sprint(finder))
+	}
+	if err := g.generate(root, 0); err != nil {
+		return nil, err
+	}
+	return g.data, nil
+}
+
+// height returns the minimum depth needed to finish node.
+func (g *generation) height(node *syntaxNode) int {
+	switch node.kind {
+	case syntaxRule:
+		return g.heights[node.label]
+	case syntaxConcatenation:
+		height := 0
+		for _, child := range node.children {
+			height = max(height, g.height(child))
+		}
+		return min(height+1, unreachable)
+	case syntaxAlternatives:
+		height := unreachable
+		for _, child := range node.children {
+			height = min(height, g.height(child))
+		}
+		return min(height+1, unreachable)
+	case syntaxRepetition:
+		if node.min == 0 {
+			return 1
+		}
+		return min(g.height(node.children[0])+1, unreachable)
+	}
+	return 0
+}
+
+func (g *generation) generate(node *syntaxNode, depth int) error {
+	limited := depth >= g.generator.maxDepth
+	switch node.kind {
+	case syntaxRule:
+		return g.generate(g.rules[node.label], depth+1)
+	case syntaxConcatenation:
+		for _, child := range node.children {
+			if err := g.generate(child, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case syntaxAlternatives:
+		candidates := []*syntaxNode{}
+		for _, child := range node.children {
+			if g.height(child) >= unreachable {
+				continue
+			}
+			if limited && g.height(child)+1 > g.height(node) {
+				continue
+			}
+			candidates = append(candidates, child)
+		}
+		return g.generate(candidates[g.generator.rand.Intn(len(candidates))], depth+1)
+	case syntaxRepetition:
+		count := node.min
+		// NOTE
+		// If the child never finishes, the repetition finishes only with no repetitions.
+		if !limited && g.height(node.children[0]) < unreachable {
+			upper := node.min + g.generator.maxRepeat
+			if node.max >= 0 && node.max < upper {
+				upper = node.max
+			}
+			count += g.generator.rand.Intn(upper - node.min + 1)
+		}
+		for i := 0; i < count; i++ {
+			if err := g.generate(node.children[0], depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return g.generateTerminal(node)
+}
+
+func (g *generation) generateTerminal(node *syntaxNode) error {
+	switch f := node.finder.(type) {
+	case ByteFinder:
+		g.data = append(g.data, f.target)
+	case *ByteFinder:
+		g.data = append(g.data, f.target)
+	case BytesFinder:
+		return g.generateBytes(node, f.target)
+	case *BytesFinder:
+		return g.generateBytes(node, f.target)
+	case CaseInsensitiveBytesFinder:
+		return g.generateCaseInsensitiveBytes(node, f.target)
+	case *CaseInsensitiveBytesFinder:
+		return g.generateCaseInsensitiveBytes(node, f.target)
+	case CrLfFinder, *CrLfFinder:
+		g.data = append(g.data, '\r', '\n')
+	case *ValueRangeAlternativesFinder:
+		g.data = append(g.data, f.rangeStart+byte(g.generator.rand.Intn(int(f.rangeEnd-f.rangeStart)+1)))
+	default:
+		return fmt.Errorf("abnfp: can not generate the data of %v", node.label)
+	}
+	return nil
+}
+
+func (g *generation) generateBytes(node *syntaxNode, target []byte) error {
+	if len(target) == 0 {
+		return fmt.Errorf("abnfp: can not generate the data of %v", node.label)
+	}
+	g.data = append(g.data, target...)
+	return nil
+}
+
+// generateCaseInsensitiveBytes generates target whose letters are in random cases.
+func (g *generation) generateCaseInsensitiveBytes(node *syntaxNode, target []byte) error {
+	if len(target) == 0 {
+		return fmt.Errorf("abnfp: can not generate the data of %v", node.label)
+	}
+	for _, b := range target {
+		if g.generator.rand.Intn(2) == 0 {
+			b = toLowerByte(b)
+		} else {
+			b = toUpperByte(b)
+		}
+		g.data = append(g.data, b)
+	}
+	return nil
+}
+
+// NewGenerator returns a Generator whose random numbers are generated from seed.
+// The same seed generates the same data.
+func NewGenerator(seed int64, maxDepth int, maxRepeat int) *Generator {
+	return &Generator{rand: rand.New(rand.NewSource(seed)), maxDepth: maxDepth, maxRepeat: maxRepeat}
+}
@@ -0,0 +1,153 @@
+package abnfp
+
+import (
+	"testing"
+)
+
+// endOfDataFinder finds the end of the data.
+type endOfDataFinder struct{}
+
+func (finder endOfDataFinder) Find(data []byte) (found bool, end int) {
+	return len(data) == 0, 0
+}
+
+func (finder endOfDataFinder) Copy() Finder {
+	return endOfDataFinder{}
+}
+
+// findsAll returns true if finder finds all of data.
+func findsAll(finder Finder, data []byte) bool {
+	found, _ := NewConcatenationFinder([]Finder{finder, endOfDataFinder{}}).Find(data)
+	return found
+}
+
+func TestGenerator(t *testing.T) {
+	type TestCase struct {
+		testName string
+		finder   Finder
+	}
+
+	// list = item *( "," item )
+	// item = 1*DIGIT / "(" list ")"
+	var list *RuleFinder
+	item := NewRuleFinder("item", func() Finder {
+		return NewAlternativesFinder([]Finder{
+			NewConcatenationFinder([]Finder{NewByteFinder('('), list, NewByteFinder(')')}),
+			NewVariableRepetitionMinFinder(1, NewDigitFinder()),
+		})
+	})
+	list = NewRuleFinder("list", func() Finder {
+		return NewConcatenationFinder([]Finder{
+			item,
+			NewVariableRepetitionFinder(NewConcatenationFinder([]Finder{NewByteFinder(','), item})),
+		})
+	})
+
+	tests := []TestCase{
+		{
+			testName: "ALPHA",
+			finder:   NewAlphaFinder(),
+		},
+		{
+			testName: "\"GET\" 1*ALPHA",
+			finder: NewConcatenationFinder([]Finder{
+				NewCaseInsensitiveBytesFinder([]byte("GET")),
+				NewVariableRepetitionMinFinder(1, NewAlphaFinder()),
+			}),
+		},
+		{
+			testName: "CRLF 2*3( \"ab\" / HEXDIG ) [ %x20 ]",
+			finder: NewConcatenationFinder([]Finder{
+				NewCrLfFinder(),
+				NewVariableRepetitionMinMaxFinder(2, 3, NewAlternativesFinder([]Finder{
+					NewBytesFinder([]byte("ab")),
+					NewHexDigFinder(),
+				})),
+				NewOptionalSequenceFinder(NewSpFinder()),
+			}),
+		},
+		{
+			testName: "*ALPHA ALPHA",
+			finder: NewConcatenationFinder([]Finder{
+				NewVariableRepetitionFinder(NewAlphaFinder()),
+				NewAlphaFinder(),
+			}),
+		},
+		{
+			testName: "recursive rules",
+			finder:   list,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			generator := NewGenerator(1, 10, 5)
+			for i := 0; i < 100; i++ {
+				data, err := generator.Generate(testCase.finder)
+				equals(testCase.testName, t, nil, err)
+				if !findsAll(testCase.finder, data) {
+					t.Errorf("%v: generated data %q is not found", testCase.testName, data)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratorSeed(t *testing.T) {
+	generate := func(seed int64) string {
+		generator := NewGenerator(seed, 10, 5)
+		data := []byte{}
+		for i := 0; i < 10; i++ {
+			generated, _ := generator.Generate(NewVariableRepetitionFinder(NewAlphaFinder()))
+			data = append(data, generated...)
+		}
+		return string(data)
+	}
+	equals("same seed", t, generate(1), generate(1))
+	if generate(1) == generate(2) {
+		t.Errorf("different seeds generate the same data: %v", generate(1))
+	}
+}
+
+func TestGeneratorMaxDepth(t *testing.T) {
+	// nest = "(" nest ")" / "x"
+	var nest *RuleFinder
+	nest = NewRuleFinder("nest", func() Finder {
+		return NewAlternativesFinder([]Finder{
+			NewConcatenationFinder([]Finder{NewByteFinder('('), nest, NewByteFinder(')')}),
+			NewByteFinder('x'),
+		})
+	})
+	generator := NewGenerator(1, 0, 5)
+	for i := 0; i < 10; i++ {
+		data, err := generator.Generate(nest)
+		equals("maxDepth 0", t, nil, err)
+		equals("maxDepth 0", t, "x", string(data))
+	}
+	generator = NewGenerator(1, 8, 5)
+	for i := 0; i < 100; i++ {
+		data, _ := generator.Generate(nest)
+		// Each nest is 2 depths deep, the rule and the alternatives.
+		if len(data) > 9 {
+			t.Errorf("maxDepth 8: generated data %q is too deep", data)
+		}
+	}
+}
+
+func TestGeneratorError(t *testing.T) {
+	// loop = "a" loop
+	var loop *RuleFinder
+	loop = NewRuleFinder("loop", func() Finder {
+		return NewConcatenationFinder([]Finder{NewByteFinder('a'), loop})
+	})
+	_, err := NewGenerator(1, 10, 5).Generate(loop)
+	equals("loop", t, "abnfp: loop never finishes", err.Error())
+
+	_, err = NewGenerator(1, 10, 5).Generate(NewConcatenationFinder([]Finder{endOfDataFinder{}}))
+	equals("endOfDataFinder", t, "abnfp: can not generate the data of <abnfp.endOfDataFinder>", err.Error())
+
+	// A repetition of the rule which never finishes can finish with no repetitions.
+	data, err := NewGenerator(1, 10, 5).Generate(NewVariableRepetitionFinder(loop))
+	equals("*loop", t, nil, err)
+	equals("*loop", t, "", string(data))
+}
@@ -1,3 +1,3 @@
 module github.com/um7a/abnf-parser
 
-go 1.19
\ No newline at end of file
+go 1.21
\ No newline at end of file
@@ -0,0 +1,297 @@
+package abnfp
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrUndefinedRule is returned when a rule is referred or extended, but it is
+// not defined.
+var ErrUndefinedRule = errors.New("abnfp: undefined rule")
+
+// ruleBuilder builds the Finder of an alternative of a rule.
+// rule returns the Finder of the rule referred by name.
+type ruleBuilder func(rule func(name string) Finder) Finder
+
+// renamed returns the ruleBuilder which refers the rules in names with prefix.
+func (builder ruleBuilder) renamed(prefix string, names map[string]bool) ruleBuilder {
+	if prefix == "" {
+		return builder
+	}
+	return func(rule func(name string) Finder) Finder {
+		return builder(func(name string) Finder {
+			if names[strings.ToLower(name)] {
+				return rule(prefix + name)
+			}
+			return rule(name)
+		})
+	}
+}
+
+func newFinderRuleBuilder(finder Finder) ruleBuilder {
+	finder = finder.Copy()
+	return func(rule func(name string) Finder) Finder {
+		return finder.Copy()
+	}
+}
+
+type grammarRule struct {
+	name         string
+	alternatives []ruleBuilder
+}
+
+// RFC5234 - 2.1. Rule Naming
+// The name of a rule is simply the name itself, that is, a sequence of
+// characters, beginning with an alphabetic character, and followed by a
+// combination of alphabetics, digits, and hyphens (dashes).
+//
+// NOTE:
+// Rule names are case insensitive.
+//
+// Grammar is a set of rules which refer each other by name.
+// The rules are defined with Define and Compile, extended with
+// DefineAlternative and "=/", and imported from the other Grammars with Import.
+// The core rules of RFC5234 - B.1, like ALPHA and DIGIT, can be referred
+// without defining them.
+//
+// The rules are looked up when they are found, so the rules can be referred
+// before they are defined, and redefining a rule changes the rules referring
+// it. A Grammar must not be changed while it is used to find the syntax.
+
+type Grammar struct {
+	rules map[string]*grammarRule
+	// names are the lower case names of the rules in the order in which they
+	// are defined first.
+	names []string
+}
+
+func NewGrammar() *Grammar {
+	return &Grammar{rules: map[string]*grammarRule{}, names: []string{}}
+}
+
+var coreRules = map[string]func() Finder{
+	"alpha":  func() Finder { return NewAlphaFinder() },
+	"bit":    func() Finder { return NewBitFinder() },
+	"char":   func() Finder { return NewCharFinder() },
+	"cr":     func() Finder { return NewCrFinder() },
+	"crlf":   func() Finder { return NewCrLfFinder() },
+	"ctl":    func() Finder { return NewCtlFinder() },
+	"digit":  func() Finder { return NewDigitFinder() },
+	"dquote": func() Finder { return NewDQuoteFinder() },
+	"hexdig": func() Finder { return NewHexDigFinder() },
+	"htab":   func() Finder { return NewHTabFinder() },
+	"lf":     func() Finder { return NewLfFinder() },
+	"lwsp":   func() Finder { return NewLwspFinder() },
+	"octet":  func() Finder { return NewOctetFinder() },
+	"sp":     func() Finder { return NewSpFinder() },
+	"vchar":  func() Finder { return NewVCharFinder() },
+	"wsp":    func() Finder { return NewWspFinder() },
+}
+
+func (g *Grammar) define(name string, alternatives []ruleBuilder) {
+	key := strings.ToLower(name)
+	if _, ok := g.rules[key]; !ok {
+		g.names = append(g.names, key)
+	}
+	g.rules[key] = &grammarRule{name: name, alternatives: alternatives}
+}
+
+// Define defines the rule name as finder. If the rule is already defined, the
+// definition is overridden.
+// finder can refer the rules of the Grammar with Rule.
+func (g *Grammar) Define(name string, finder Finder) {
+	g.define(name, []ruleBuilder{newFinderRuleBuilder(finder)})
+}
+
+// DefineAlternative appends finder to the alternatives of the rule name, like
+//
+//	name =/ finder
+//
+// It returns ErrUndefinedRule if the rule is not defined.
+func (g *Grammar) DefineAlternative(name string, finder Finder) error {
+	return g.defineAlternative(name, newFinderRuleBuilder(finder))
+}
+
+func (g *Grammar) defineAlternative(name string, alternative ruleBuilder) error {
+	rule, ok := g.rules[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrUndefinedRule, name)
+	}
+	rule.alternatives = append(rule.alternatives, alternative)
+	return nil
+}
+
+// Import imports the rules in names and the rules referred by them from
+// other. All the rules of other are imported if names is empty.
+// If namespace is not empty, the imported rules are named like
+// "namespace.name", and they refer each other with the names.
+// The rules which are already defined in g are not imported, so g can define
+// the rules before importing them to override the definitions of other.
+//
+// It returns ErrUndefinedRule if a rule in names is not defined in other.
+//
+// NOTE
+// The Finders defined with Define refer the rules of the Grammar given to
+// Rule, so they are not renamed by namespace.
+func (g *Grammar) Import(other *Grammar, namespace string, names ...string) error {
+	keys := append([]string{}, other.names...)
+	if len(names) != 0 {
+		keys = []string{}
+		for _, name := range names {
+			key := strings.ToLower(name)
+			if _, ok := other.rules[key]; !ok {
+				return fmt.Errorf("%w: %v", ErrUndefinedRule, name)
+			}
+			keys = append(keys, key)
+		}
+		keys = other.dependencies(keys)
+	}
+	imported := map[string]bool{}
+	for _, key := range keys {
+		imported[key] = true
+	}
+	prefix := ""
+	if namespace != "" {
+		prefix = namespace + "."
+	}
+	for _, key := range keys {
+		rule := other.rules[key]
+		name := prefix + rule.name
+		if _, ok := g.rules[strings.ToLower(name)]; ok {
+			continue
+		}
+		alternatives := []ruleBuilder{}
+		for _, alternative := range rule.alternatives {
+			alternatives = append(alternatives, alternative.renamed(prefix, imported))
+		}
+		g.define(name, alternatives)
+	}
+	return nil
+}
+
+// references returns the names of the rules referred by the rule key.
+func (g *Grammar) references(key string) []string {
+	names := []string{}
+	record := func(name string) Finder {
+		names = append(names, name)
+		return NewAlternativesFinder([]Finder{})
+	}
+	for _, alternative := range g.rules[key].alternatives {
+		alternative(record)
+	}
+	return names
+}
+
+// dependencies returns keys and the lower case names of the rules referred by
+// them directly or indirectly, which are defined in g.
+func (g *Grammar) dependencies(keys []string) []string {
+	added := map[string]bool{}
+	dependencies := []string{}
+	for i := 0; i < len(keys); i++ {
+		key := keys[i]
+		if added[key] {
+			continue
+		}
+		added[key] = true
+		dependencies = append(dependencies, key)
+		for _, name := range g.references(key) {
+			if _, ok := g.rules[strings.ToLower(name)]; ok {
+				keys = append(keys, strings.ToLower(name))
+			}
+		}
+	}
+	return dependencies
+}
+
+// Rule returns the RuleFinder of the rule name.
+// It finds nothing while the rule is not defined.
+func (g *Grammar) Rule(name string) *RuleFinder {
+	key := strings.ToLower(name)
+	if rule, ok := g.rules[key]; ok {
+		name = rule.name
+	}
+	return NewRuleFinder(name, func() Finder {
+		return g.newFinder(key)
+	})
+}
+
+func (g *Grammar) newFinder(key string) Finder {
+	rule, ok := g.rules[key]
+	if !ok {
+		if core, ok := coreRules[key]; ok {
+			return core()
+		}
+		return NewAlternativesFinder([]Finder{})
+	}
+	if len(rule.alternatives) == 1 {
+		return rule.alternatives[0](g.reference)
+	}
+	finders := []Finder{}
+	for _, alternative := range rule.alternatives {
+		finders = append(finders, alternative(g.reference))
+	}
+	return NewAlternativesFinder(finders)
+}
+
+// reference returns the Finder of the rule name referred by the other rule.
+// The core rules are not RuleFinders unless they are defined in g.
+func (g *Grammar) reference(name string) Finder {
+	key := strings.ToLower(name)
+	if _, ok := g.rules[key]; !ok {
+		if core, ok := coreRules[key]; ok {
+			return core()
+		}
+	}
+	return g.Rule(name)
+}
+
+// HasRule returns true if the rule name is defined in g.
+func (g *Grammar) HasRule(name string) bool {
+	_, ok := g.rules[strings.ToLower(name)]
+	return ok
+}
+
+// RuleNames returns the names of the rules in the order in which they are
+// defined first.
+func (g *Grammar) RuleNames() []string {
+	names := []string{}
+	for _, key := range g.names {
+		names = append(names, g.rules[key].name)
+	}
+	return names
+}
+
+// Check returns ErrUndefinedRule listing the rules which are referred but not
+// defined, like
+//
+//	abnfp: undefined rule: host, port
+//
+// It returns nil if all of them are defined.
+func (g *Grammar) Check() error {
+	undefined := map[string]string{}
+	for _, key := range g.names {
+		for _, name := range g.references(key) {
+			referredKey := strings.ToLower(name)
+			if _, ok := g.rules[referredKey]; ok {
+				continue
+			}
+			if _, ok := coreRules[referredKey]; ok {
+				continue
+			}
+			if _, ok := undefined[referredKey]; !ok {
+				undefined[referredKey] = name
+			}
+		}
+	}
+	if len(undefined) == 0 {
+		return nil
+	}
+	names := []string{}
+	for _, name := range undefined {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("%w: %v", ErrUndefinedRule, strings.Join(names, ", "))
+}
@@ -0,0 +1,257 @@
+package abnfp
+
+import (
+	"errors"
+	"testing"
+)
+
+func mustCompileGrammar(t *testing.T, abnf string) *Grammar {
+	t.Helper()
+	g, err := CompileGrammar([]byte(abnf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestGrammar(t *testing.T) {
+	type TestCase struct {
+		testName          string
+		grammar           func(t *testing.T) *Grammar
+		rule              string
+		data              []byte
+		expectedParsed    []byte
+		expectedRemaining []byte
+	}
+
+	uri := "uri = scheme \":\" host\n" +
+		"scheme = 1*ALPHA\n" +
+		"host = 1*( ALPHA / \".\" )\n"
+
+	tests := []TestCase{
+		{
+			testName: "data: []byte(\"ab1\"), parse Define(word, 1*ALPHA)",
+			grammar: func(t *testing.T) *Grammar {
+				g := NewGrammar()
+				g.Define("word", NewVariableRepetitionMinFinder(1, NewAlphaFinder()))
+				return g
+			},
+			rule:              "word",
+			data:              []byte("ab1"),
+			expectedParsed:    []byte("ab"),
+			expectedRemaining: []byte("1"),
+		},
+		{
+			testName: "data: []byte(\"a,b\"), parse Define(list, item *(\",\" item)) with Rule",
+			grammar: func(t *testing.T) *Grammar {
+				g := NewGrammar()
+				g.Define("list", NewConcatenationFinder([]Finder{
+					g.Rule("item"),
+					NewVariableRepetitionFinder(NewConcatenationFinder([]Finder{NewByteFinder(','), g.Rule("item")})),
+				}))
+				g.Define("item", NewAlphaFinder())
+				return g
+			},
+			rule:              "list",
+			data:              []byte("a,b"),
+			expectedParsed:    []byte("a,b"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName: "data: []byte(\"1\"), parse DefineAlternative(word, DIGIT)",
+			grammar: func(t *testing.T) *Grammar {
+				g := mustCompileGrammar(t, "word = 1*ALPHA\n")
+				if err := g.DefineAlternative("WORD", NewDigitFinder()); err != nil {
+					t.Fatal(err)
+				}
+				return g
+			},
+			rule:              "word",
+			data:              []byte("1"),
+			expectedParsed:    []byte("1"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName: "data: []byte(\"1\"), parse the rule overridden by Define",
+			grammar: func(t *testing.T) *Grammar {
+				g := mustCompileGrammar(t, "word = 1*ALPHA\n")
+				g.Define("word", NewDigitFinder())
+				return g
+			},
+			rule:              "word",
+			data:              []byte("1"),
+			expectedParsed:    []byte("1"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName: "data: []byte(\"1\"), parse the core rule overridden by Compile",
+			grammar: func(t *testing.T) *Grammar {
+				return mustCompileGrammar(t, "word = 1*ALPHA\nALPHA = DIGIT\n")
+			},
+			rule:              "word",
+			data:              []byte("1"),
+			expectedParsed:    []byte("1"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName: "data: []byte(\"http:a.b\"), parse the imported rule",
+			grammar: func(t *testing.T) *Grammar {
+				g := NewGrammar()
+				if err := g.Import(mustCompileGrammar(t, uri), "", "uri"); err != nil {
+					t.Fatal(err)
+				}
+				return g
+			},
+			rule:              "uri",
+			data:              []byte("http:a.b"),
+			expectedParsed:    []byte("http:a.b"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName: "data: []byte(\"http:a.b\"), parse the rule imported with namespace",
+			grammar: func(t *testing.T) *Grammar {
+				g := mustCompileGrammar(t, "host = DIGIT\n")
+				if err := g.Import(mustCompileGrammar(t, uri), "rfc3986"); err != nil {
+					t.Fatal(err)
+				}
+				return g
+			},
+			rule:              "rfc3986.uri",
+			data:              []byte("http:a.b"),
+			expectedParsed:    []byte("http:a.b"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName: "data: []byte(\"http:1\"), parse the imported rule referring the rule defined before Import",
+			grammar: func(t *testing.T) *Grammar {
+				g := mustCompileGrammar(t, "host = DIGIT\n")
+				if err := g.Import(mustCompileGrammar(t, uri), ""); err != nil {
+					t.Fatal(err)
+				}
+				return g
+			},
+			rule:              "uri",
+			data:              []byte("http:1"),
+			expectedParsed:    []byte("http:1"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName: "data: []byte(\"http:1\"), parse the imported rule extended with =/",
+			grammar: func(t *testing.T) *Grammar {
+				g := NewGrammar()
+				if err := g.Import(mustCompileGrammar(t, uri), ""); err != nil {
+					t.Fatal(err)
+				}
+				if err := g.Compile([]byte("host =/ 1*DIGIT\n")); err != nil {
+					t.Fatal(err)
+				}
+				return g
+			},
+			rule:              "uri",
+			data:              []byte("http:1"),
+			expectedParsed:    []byte("http:1"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName: "data: []byte(\"a\"), parse the rule not defined",
+			grammar: func(t *testing.T) *Grammar {
+				return NewGrammar()
+			},
+			rule:              "word",
+			data:              []byte("a"),
+			expectedParsed:    []byte(""),
+			expectedRemaining: []byte("a"),
+		},
+		{
+			testName: "data: []byte(\"a\"), parse the core rule",
+			grammar: func(t *testing.T) *Grammar {
+				return NewGrammar()
+			},
+			rule:              "alpha",
+			data:              []byte("a"),
+			expectedParsed:    []byte("a"),
+			expectedRemaining: []byte(""),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			g := testCase.grammar(t)
+			actualParsed, actualRemaining := Parse(testCase.data, g.Rule(testCase.rule))
+			sliceEquals(testCase.testName, t, testCase.expectedParsed, actualParsed)
+			sliceEquals(testCase.testName, t, testCase.expectedRemaining, actualRemaining)
+		})
+	}
+}
+
+func TestGrammarImport(t *testing.T) {
+	other := mustCompileGrammar(t, "a = b c\nb = \"b\"\nc = \"c\"\nd = \"d\"\n")
+
+	g := NewGrammar()
+	equals("Import", t, nil, g.Import(other, "", "B", "a"))
+	sliceEquals("RuleNames", t, []string{"b", "a", "c"}, g.RuleNames())
+	equals("HasRule", t, true, g.HasRule("A"))
+	equals("HasRule", t, false, g.HasRule("d"))
+
+	g = NewGrammar()
+	equals("Import", t, nil, g.Import(other, "x"))
+	sliceEquals("RuleNames", t, []string{"x.a", "x.b", "x.c", "x.d"}, g.RuleNames())
+	equals("String", t, "x.a = x.b x.c\nx.b = \"b\"\nx.c = \"c\"\n", FormatRules(g.Rule("x.a")))
+
+	// The rules imported with the namespace are referred from ABNF.
+	equals("Compile", t, nil, g.Compile([]byte("e = x.a \"e\" / X.D\n")))
+	parsed, _ := Parse([]byte("bce"), g.Rule("e"))
+	equals("Parse", t, "bce", string(parsed))
+	parsed, _ = Parse([]byte("d"), g.Rule("e"))
+	equals("Parse", t, "d", string(parsed))
+	equals("Check", t, nil, g.Check())
+	// The text of FormatRules is re-parseable.
+	text, err := FormatABNF(g.Rule("x.a"))
+	equals("FormatABNF", t, nil, err)
+	reparsed, err := CompileGrammar([]byte(text))
+	equals("CompileGrammar", t, nil, err)
+	sliceEquals("RuleNames", t, []string{"x.a", "x.b", "x.c"}, reparsed.RuleNames())
+
+	err = NewGrammar().Import(other, "", "e")
+	equals("Import undefined rule", t, true, errors.Is(err, ErrUndefinedRule))
+	equals("Import undefined rule", t, "abnfp: undefined rule: e", err.Error())
+
+	err = NewGrammar().DefineAlternative("e", NewAlphaFinder())
+	equals("DefineAlternative undefined rule", t, true, errors.Is(err, ErrUndefinedRule))
+}
+
+func TestGrammarCheck(t *testing.T) {
+	type TestCase struct {
+		testName    string
+		abnf        string
+		expectedErr string
+	}
+
+	tests := []TestCase{
+		{
+			testName:    "all rules defined",
+			abnf:        "a = b DIGIT\nb = \"b\" / a\n",
+			expectedErr: "",
+		},
+		{
+			testName:    "undefined rules",
+			abnf:        "a = Port host\nb = port / a\n",
+			expectedErr: "abnfp: undefined rule: Port, host",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			err := mustCompileGrammar(t, testCase.abnf).Check()
+			if testCase.expectedErr == "" {
+				equals(testCase.testName, t, nil, err)
+				return
+			}
+			if err == nil {
+				t.Fatalf("%v: expected err: %v, actual: nil", testCase.testName, testCase.expectedErr)
+			}
+			equals(testCase.testName, t, true, errors.Is(err, ErrUndefinedRule))
+			equals(testCase.testName, t, testCase.expectedErr, err.Error())
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package abnfp
+
+// AndFinder finds the empty data if its child Finder can find the syntax.
+// It is the positive lookahead, e.g. "a" And("b") finds "a" only before "b".
+
+type AndFinder struct {
+	childFinder Finder
+}
+
+func (finder AndFinder) Find(data []byte) (found bool, end int) {
+	childFound, _ := finder.childFinder.Copy().Find(data)
+	return childFound, 0
+}
+
+func (finder AndFinder) Copy() Finder {
+	return AndFinder{childFinder: finder.childFinder.Copy()}
+}
+
+func (finder AndFinder) setParser(p *parser) {
+	setParser(finder.childFinder, p)
+}
+
+func NewAndFinder(finder Finder) *AndFinder {
+	return &AndFinder{childFinder: finder.Copy()}
+}
+
+// NotFinder finds the empty data if its child Finder can't find the syntax.
+// It is the negative lookahead, e.g. Not(DQUOTE) VCHAR finds VCHAR except DQUOTE.
+
+type NotFinder struct {
+	childFinder Finder
+}
+
+func (finder NotFinder) Find(data []byte) (found bool, end int) {
+	childFound, _ := finder.childFinder.Copy().Find(data)
+	return !childFound, 0
+}
+
+func (finder NotFinder) Copy() Finder {
+	return NotFinder{childFinder: finder.childFinder.Copy()}
+}
+
+func (finder NotFinder) setParser(p *parser) {
+	setParser(finder.childFinder, p)
+}
+
+func NewNotFinder(finder Finder) *NotFinder {
+	return &NotFinder{childFinder: finder.Copy()}
+}
+
+// ExceptFinder finds the data found by its base Finder, except the data which
+// is also the syntax of its excluded Finder.
+// e.g. Except(token, "GET") finds the tokens other than "GET", but it finds
+// "GETS", because "GETS" is not all the syntax of "GET".
+//
+// NOTE
+// When the data found by the base Finder is excluded, the base Finder is
+// recalculated, and ExceptFinder finds the other data of it, which might be
+// shorter. e.g. Except(token, "GET") finds "GE" from "GET x", not fails.
+// Wrap the base Finder with PossessiveFinder to fail when its first data is
+// excluded, like "a - b" of the other grammar tools.
+
+type ExceptFinder struct {
+	baseFinder     Finder
+	excludedFinder Finder
+}
+
+func (finder *ExceptFinder) Find(data []byte) (found bool, end int) {
+	found, end = finder.baseFinder.Find(data)
+	return finder.skipExcluded(data, found, end)
+}
+
+// skipExcluded recalculates baseFinder until it finds the data which is not
+// excluded.
+func (finder *ExceptFinder) skipExcluded(data []byte, found bool, end int) (bool, int) {
+	for found && finder.excludes(data[:end]) {
+		baseFinder, ok := finder.baseFinder.(VariableFinder)
+		if !ok {
+			return false, 0
+		}
+		found, end = baseFinder.Recalculate(data)
+	}
+	if !found {
+		return false, 0
+	}
+	return true, end
+}
+
+// excludes returns true if excludedFinder finds all of data.
+func (finder *ExceptFinder) excludes(data []byte) bool {
+	found, _ := NewConcatenationFinder([]Finder{
+		finder.excludedFinder,
+		NewNotFinder(NewOctetFinder()),
+	}).Find(data)
+	return found
+}
+
+func (finder ExceptFinder) Copy() Finder {
+	return &ExceptFinder{baseFinder: finder.baseFinder.Copy(), excludedFinder: finder.excludedFinder.Copy()}
+}
+
+func (finder *ExceptFinder) Recalculate(data []byte) (found bool, end int) {
+	baseFinder, ok := finder.baseFinder.(VariableFinder)
+	if !ok {
+		return false, 0
+	}
+	found, end = baseFinder.Recalculate(data)
+	return finder.skipExcluded(data, found, end)
+}
+
+func (finder *ExceptFinder) foundChildren(end int) []foundChild {
+	return []foundChild{{finder: finder.baseFinder, start: 0, end: end}}
+}
+
+func (finder *ExceptFinder) setParser(p *parser) {
+	setParser(finder.baseFinder, p)
+	setParser(finder.excludedFinder, p)
+}
+
+func NewExceptFinder(base Finder, excluded Finder) *ExceptFinder {
+	return &ExceptFinder{baseFinder: base.Copy(), excludedFinder: excluded.Copy()}
+}
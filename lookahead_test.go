@@ -0,0 +1,202 @@
+package abnfp
+
+import (
+	"testing"
+)
+
+func TestAndFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"1\"), find &DIGIT",
+			data:          []byte("1"),
+			finder:        NewAndFinder(NewDigitFinder()),
+			expectedFound: true,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"a\"), find &DIGIT",
+			data:          []byte("a"),
+			finder:        NewAndFinder(NewDigitFinder()),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName: "data: []byte(\"a1\"), find ALPHA &DIGIT",
+			data:     []byte("a1"),
+			finder: NewConcatenationFinder([]Finder{
+				NewAlphaFinder(),
+				NewAndFinder(NewDigitFinder()),
+			}),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+		{
+			testName: "data: []byte(\"ab\"), find ALPHA &DIGIT",
+			data:     []byte("ab"),
+			finder: NewConcatenationFinder([]Finder{
+				NewAlphaFinder(),
+				NewAndFinder(NewDigitFinder()),
+			}),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+
+	execFinderTest(tests, t)
+}
+
+func TestNotFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"a\"), find !DIGIT",
+			data:          []byte("a"),
+			finder:        NewNotFinder(NewDigitFinder()),
+			expectedFound: true,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte{}, find !OCTET",
+			data:          []byte{},
+			finder:        NewNotFinder(NewOctetFinder()),
+			expectedFound: true,
+			expectedEnd:   0,
+		},
+		{
+			testName: "data: []byte(\"\\\"\"), find !DQUOTE VCHAR",
+			data:     []byte("\""),
+			finder: NewConcatenationFinder([]Finder{
+				NewNotFinder(NewDQuoteFinder()),
+				NewVCharFinder(),
+			}),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName: "data: []byte(\"ab\\\"\"), find *( !DQUOTE VCHAR )",
+			data:     []byte("ab\""),
+			finder: NewVariableRepetitionFinder(NewConcatenationFinder([]Finder{
+				NewNotFinder(NewDQuoteFinder()),
+				NewVCharFinder(),
+			})),
+			expectedFound: true,
+			expectedEnd:   2,
+		},
+		//
+		// NOTE
+		// In this test case, *ALPHA finds "ab" first, then it is recalculated to find "a",
+		// because "b" is followed by "c".
+		//
+		{
+			testName: "data: []byte(\"abc\"), find *ALPHA !\"c\"",
+			data:     []byte("abc"),
+			finder: NewConcatenationFinder([]Finder{
+				NewVariableRepetitionMaxFinder(2, NewAlphaFinder()),
+				NewNotFinder(NewByteFinder('c')),
+			}),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+	}
+
+	execFinderTest(tests, t)
+}
+
+func TestExceptFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"a\"), find VCHAR except DQUOTE",
+			data:          []byte("a"),
+			finder:        NewExceptFinder(NewVCharFinder(), NewDQuoteFinder()),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+		{
+			testName:      "data: []byte(\"\\\"\"), find VCHAR except DQUOTE",
+			data:          []byte("\""),
+			finder:        NewExceptFinder(NewVCharFinder(), NewDQuoteFinder()),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"GET\"), find 1*ALPHA except \"GET\"",
+			data:          []byte("GET"),
+			finder:        NewExceptFinder(NewVariableRepetitionMinFinder(1, NewAlphaFinder()), NewBytesFinder([]byte("GET"))),
+			expectedFound: true,
+			expectedEnd:   2,
+		},
+		{
+			testName:      "data: []byte(\"GETS\"), find 1*ALPHA except \"GET\"",
+			data:          []byte("GETS"),
+			finder:        NewExceptFinder(NewVariableRepetitionMinFinder(1, NewAlphaFinder()), NewBytesFinder([]byte("GET"))),
+			expectedFound: true,
+			expectedEnd:   4,
+		},
+		{
+			testName:      "data: []byte(\"ab\"), find 1*ALPHA except 1*ALPHA",
+			data:          []byte("ab"),
+			finder:        NewExceptFinder(NewVariableRepetitionMinFinder(1, NewAlphaFinder()), NewVariableRepetitionMinFinder(1, NewAlphaFinder())),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName: "data: []byte(\"GET\"), find ( 1*ALPHA except \"GET\" ) \"T\"",
+			data:     []byte("GET"),
+			finder: NewConcatenationFinder([]Finder{
+				NewExceptFinder(NewVariableRepetitionMinFinder(1, NewAlphaFinder()), NewBytesFinder([]byte("GET"))),
+				NewByteFinder('T'),
+			}),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+	}
+
+	execFinderTest(tests, t)
+}
+
+func TestExceptFinderRecalculate(t *testing.T) {
+	finder := NewExceptFinder(NewVariableRepetitionFinder(NewAlphaFinder()), NewBytesFinder([]byte("ab")))
+	data := []byte("abc")
+	found, end := finder.Find(data)
+	equals("Find", t, true, found)
+	equals("Find", t, 3, end)
+	ends := []int{}
+	for {
+		found, end = finder.Recalculate(data)
+		if !found {
+			break
+		}
+		ends = append(ends, end)
+	}
+	sliceEquals("Recalculate", t, []int{1, 0}, ends)
+}
+
+// ExceptFinder backtracks the base Finder when its data is excluded, unless
+// it is possessive.
+func TestExceptFinderBacktrack(t *testing.T) {
+	token := NewVariableRepetitionMinFinder(1, NewAlphaFinder())
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"GET x\"), find 1*ALPHA except \"GET\"",
+			data:          []byte("GET x"),
+			finder:        NewExceptFinder(token, NewBytesFinder([]byte("GET"))),
+			expectedFound: true,
+			expectedEnd:   2,
+		},
+		{
+			testName:      "data: []byte(\"GET x\"), find Possessive(1*ALPHA) except \"GET\"",
+			data:          []byte("GET x"),
+			finder:        NewExceptFinder(NewPossessiveFinder(token), NewBytesFinder([]byte("GET"))),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"PUT x\"), find Possessive(1*ALPHA) except \"GET\"",
+			data:          []byte("PUT x"),
+			finder:        NewExceptFinder(NewPossessiveFinder(token), NewBytesFinder([]byte("GET"))),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+	}
+
+	execFinderTest(tests, t)
+}
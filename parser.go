@@ -0,0 +1,300 @@
+package abnfp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrAborted is returned when a parse is aborted by WithContext or
+// WithMaxSteps before it finds the syntax.
+var ErrAborted = errors.New("abnfp: parse aborted")
+
+// Option configures a parse.
+type Option func(p *parser)
+
+// WithTracer makes the parse report its events to tracer.
+func WithTracer(tracer Tracer) Option {
+	return func(p *parser) {
+		p.tracer = tracer
+	}
+}
+
+// WithLongestMatch makes the parse find the longest data among all the data
+// the Finder can find. It tries all of them, so it might take time exponential
+// in the data for ambiguous syntax.
+func WithLongestMatch() Option {
+	return func(p *parser) {
+		p.longestMatch = true
+	}
+}
+
+// WithProseVal binds finder to the prose-vals whose description is
+// description, so that they find the syntax of finder in the parse.
+func WithProseVal(description string, finder Finder) Option {
+	return func(p *parser) {
+		if p.proseVals == nil {
+			p.proseVals = map[string]Finder{}
+		}
+		p.proseVals[description] = finder
+	}
+}
+
+// WithContext aborts the parse when ctx is done. The parse returns the error
+// which wraps both ErrAborted and the error of ctx, like context.Canceled.
+func WithContext(ctx context.Context) Option {
+	return func(p *parser) {
+		p.ctx = ctx
+	}
+}
+
+// WithMaxSteps aborts the parse when it takes more than maxSteps steps.
+// The parse returns the error which wraps ErrAborted.
+// A step is an event of the parse, that is, entering a rule, recalculating a
+// rule or a repetition, or backtracking, so that the parses which try too many
+// choices of the data are aborted.
+func WithMaxSteps(maxSteps int) Option {
+	return func(p *parser) {
+		p.maxSteps = maxSteps
+	}
+}
+
+// WithMaxDepth aborts the parse when the rules are nested more than maxDepth
+// inside each other, like the nested comments of a malicious data. The parse
+// returns the error which wraps ErrAborted.
+// The rules are found by the recursive calls, so the depth of the nested
+// rules is limited by the stack without it.
+func WithMaxDepth(maxDepth int) Option {
+	return func(p *parser) {
+		p.maxDepth = maxDepth
+	}
+}
+
+// WithTree makes ParseAt return the tree of the rules found in the data.
+func WithTree() Option {
+	return func(p *parser) {
+		p.tree = true
+	}
+}
+
+// parser holds the state of a parse, shared by all the Finders used in it.
+// The Finders which report the events of the parse hold it.
+// A nil *parser is a parse without options.
+type parser struct {
+	length       int
+	tracer       Tracer
+	longestMatch bool
+	proseVals    map[string]Finder
+	ctx          context.Context
+	maxSteps     int
+	steps        int
+	maxDepth     int
+	// rules are the names of the rules entered or being recalculated, and not
+	// exited yet. The innermost one is the last.
+	rules []string
+	tree  bool
+}
+
+// abort is the value of the panic which aborts the parse.
+// It is recovered by find, because Find can't return the error.
+type abort struct {
+	err error
+}
+
+func newParser(data []byte, opts []Option) *parser {
+	if len(opts) == 0 {
+		return nil
+	}
+	p := &parser{length: len(data)}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// prepare returns the copy of finder used by the parse.
+func (p *parser) prepare(finder Finder) Finder {
+	finder = finder.Copy()
+	setParser(finder, p)
+	return finder
+}
+
+// find finds the syntax with finder prepared by the parser.
+// It returns the error which wraps ErrAborted if the parse is aborted.
+func (p *parser) find(finder Finder, data []byte) (found bool, end int, err error) {
+	if p == nil {
+		found, end = finder.Find(data)
+		return found, end, nil
+	}
+	// NOTE
+	// The context is checked at the steps, but the Finders without rules and
+	// backtracking take no steps. Check it before the parse too.
+	if p.ctx != nil && p.ctx.Err() != nil {
+		return false, 0, fmt.Errorf("%w: %w", ErrAborted, p.ctx.Err())
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			a, ok := r.(abort)
+			if !ok {
+				panic(r)
+			}
+			found, end, err = false, 0, a.err
+		}
+	}()
+	found, end = p.findLongest(finder, data)
+	return found, end, nil
+}
+
+// findLongest finds the syntax with finder, or the longest one if the parse
+// is WithLongestMatch.
+func (p *parser) findLongest(finder Finder, data []byte) (found bool, end int) {
+	if !p.longestMatch {
+		return finder.Find(data)
+	}
+	// Find the longest one with a copy, then find it again with finder,
+	// so that finder has the state of the longest one.
+	longest, longestEnd := -1, 0
+	finderCopy := finder.Copy()
+	found, end = finderCopy.Find(data)
+	for i := 0; found; i++ {
+		if longest < 0 || end > longestEnd {
+			longest, longestEnd = i, end
+		}
+		variableFinder, ok := finderCopy.(VariableFinder)
+		if !ok {
+			break
+		}
+		found, end = variableFinder.Recalculate(data)
+	}
+	if longest < 0 {
+		return false, 0
+	}
+	found, end = finder.Find(data)
+	for i := 0; i < longest; i++ {
+		found, end = finder.(VariableFinder).Recalculate(data)
+	}
+	return found, end
+}
+
+// offset returns the offset of data in the data of the parse.
+// data must be a suffix of the data of the parse.
+func (p *parser) offset(data []byte) int {
+	return p.length - len(data)
+}
+
+// step counts a step of the parse, and aborts it if the context is done or
+// it takes too many steps.
+func (p *parser) step() {
+	if p.ctx != nil {
+		select {
+		case <-p.ctx.Done():
+			panic(abort{err: fmt.Errorf("%w: %w", ErrAborted, p.ctx.Err())})
+		default:
+		}
+	}
+	p.steps++
+	if p.maxSteps > 0 && p.steps > p.maxSteps {
+		panic(abort{err: fmt.Errorf("%w: more than %v steps", ErrAborted, p.maxSteps)})
+	}
+}
+
+func (p *parser) enterRule(name string, data []byte) {
+	if p == nil {
+		return
+	}
+	p.step()
+	p.pushRule(name)
+	if p.tracer == nil {
+		return
+	}
+	p.tracer.EnterRule(name, p.offset(data))
+}
+
+// pushRule records that the rule is being found, so that the events inside it
+// are reported with its name.
+func (p *parser) pushRule(name string) {
+	if p == nil {
+		return
+	}
+	p.rules = append(p.rules, name)
+	if p.maxDepth > 0 && len(p.rules) > p.maxDepth {
+		panic(abort{err: fmt.Errorf("%w: more than %v nested rules", ErrAborted, p.maxDepth)})
+	}
+}
+
+// popRule records that the innermost rule is no longer being found.
+func (p *parser) popRule() {
+	p.rules = p.rules[:len(p.rules)-1]
+}
+
+// rule returns the name of the innermost rule being found, or "" if no rule
+// is being found.
+func (p *parser) rule() string {
+	if len(p.rules) == 0 {
+		return ""
+	}
+	return p.rules[len(p.rules)-1]
+}
+
+func (p *parser) exitRule(name string, data []byte, found bool, end int) {
+	if p == nil {
+		return
+	}
+	p.popRule()
+	if p.tracer == nil {
+		return
+	}
+	start := p.offset(data)
+	p.tracer.ExitRule(name, start, found, start+end)
+}
+
+func (p *parser) backtrack(data []byte) {
+	if p == nil {
+		return
+	}
+	p.step()
+	if p.tracer == nil {
+		return
+	}
+	p.tracer.Backtrack(p.rule(), p.offset(data))
+}
+
+// recalculateRepetition counts the step of recalculating a repetition.
+// It is not reported to the tracer, because it is not the event of a rule.
+func (p *parser) recalculateRepetition() {
+	if p == nil {
+		return
+	}
+	p.step()
+}
+
+func (p *parser) recalculateRule(name string, data []byte, found bool, end int) {
+	if p == nil {
+		return
+	}
+	p.popRule()
+	p.step()
+	if p.tracer == nil {
+		return
+	}
+	start := p.offset(data)
+	p.tracer.Recalculate(name, start, found, start+end)
+}
+
+// parserSetter is implemented by the Finders which hold the parser or have
+// child Finders.
+type parserSetter interface {
+	setParser(p *parser)
+}
+
+// setParser sets p to finder and its child Finders.
+// The Finders defined outside of this package and their child Finders are not
+// set, so they don't report the events of the parse.
+func setParser(finder Finder, p *parser) {
+	if p == nil {
+		return
+	}
+	if setter, ok := finder.(parserSetter); ok {
+		setter.setParser(p)
+	}
+}
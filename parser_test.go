@@ -0,0 +1,255 @@
+package abnfp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWithLongestMatch(t *testing.T) {
+	type TestCase struct {
+		testName          string
+		data              []byte
+		finder            Finder
+		expectedParsed    []byte
+		expectedRemaining []byte
+	}
+
+	tests := []TestCase{
+		{
+			testName: "data: []byte(\"abc\"), parse \"a\" / \"ab\"",
+			data:     []byte("abc"),
+			finder: NewAlternativesFinder([]Finder{
+				NewByteFinder('a'),
+				NewBytesFinder([]byte("ab")),
+			}),
+			expectedParsed:    []byte("ab"),
+			expectedRemaining: []byte("c"),
+		},
+		{
+			testName: "data: []byte(\"abab\"), parse *( \"a\" / \"ab\" ) [ \"b\" ]",
+			data:     []byte("abab"),
+			finder: NewConcatenationFinder([]Finder{
+				NewVariableRepetitionFinder(NewAlternativesFinder([]Finder{
+					NewByteFinder('a'),
+					NewBytesFinder([]byte("ab")),
+				})),
+				NewOptionalSequenceFinder(NewByteFinder('b')),
+			}),
+			expectedParsed:    []byte("abab"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName:          "data: []byte(\"b\"), parse \"a\"",
+			data:              []byte("b"),
+			finder:            NewByteFinder('a'),
+			expectedParsed:    []byte(""),
+			expectedRemaining: []byte("b"),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			parsed, remaining := Parse(testCase.data, testCase.finder, WithLongestMatch())
+			sliceEquals(testCase.testName, t, testCase.expectedParsed, parsed)
+			sliceEquals(testCase.testName, t, testCase.expectedRemaining, remaining)
+		})
+	}
+}
+
+// The Finder has the state of the longest data after the parse.
+func TestWithLongestMatchValue(t *testing.T) {
+	word := func(data []byte, values []any) (any, error) {
+		return string(data), nil
+	}
+	finder := NewVariableRepetitionFinder(NewAlternativesFinder([]Finder{
+		NewActionFinder(NewByteFinder('a'), word),
+		NewActionFinder(NewBytesFinder([]byte("ab")), word),
+	}))
+	value, remaining, err := ParseValue([]byte("abab"), finder, WithLongestMatch())
+	equals("ParseValue", t, nil, err)
+	equals("ParseValue", t, "[ab ab]", fmt.Sprint(value))
+	sliceEquals("ParseValue", t, []byte(""), remaining)
+}
+
+func TestWithMaxSteps(t *testing.T) {
+	type TestCase struct {
+		testName          string
+		data              []byte
+		maxSteps          int
+		expectedParsed    []byte
+		expectedRemaining []byte
+		expectedErr       string
+	}
+
+	// NOTE
+	// *( "a" / "aa" ) tries all the ways to split the data into "a" and "aa"
+	// before it gives up finding "b".
+	g, err := CompileGrammar([]byte("s = *( a / aa ) \"b\"\na = \"a\"\naa = \"aa\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []TestCase{
+		{
+			testName:          "data: []byte(\"aab\"), parse in the steps",
+			data:              []byte("aab"),
+			maxSteps:          100,
+			expectedParsed:    []byte("aab"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName:          "data: []byte(\"aaa...a\"), abort after the steps",
+			data:              bytes.Repeat([]byte("a"), 40),
+			maxSteps:          1000,
+			expectedParsed:    []byte(""),
+			expectedRemaining: bytes.Repeat([]byte("a"), 40),
+			expectedErr:       "abnfp: parse aborted: more than 1000 steps",
+		},
+		{
+			testName:          "data: []byte(\"aac\"), not found in the steps",
+			data:              []byte("aac"),
+			maxSteps:          100,
+			expectedParsed:    []byte(""),
+			expectedRemaining: []byte("aac"),
+			expectedErr:       "abnfp: syntax not found",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			parsed, remaining, err := ParseContext(context.Background(), testCase.data, g.Rule("s"), WithMaxSteps(testCase.maxSteps))
+			sliceEquals(testCase.testName, t, testCase.expectedParsed, parsed)
+			sliceEquals(testCase.testName, t, testCase.expectedRemaining, remaining)
+			if testCase.expectedErr == "" {
+				equals(testCase.testName, t, nil, err)
+				return
+			}
+			if err == nil {
+				t.Fatalf("%v: expected err: %v, actual: nil", testCase.testName, testCase.expectedErr)
+			}
+			equals(testCase.testName, t, testCase.expectedErr, err.Error())
+		})
+	}
+
+	data := bytes.Repeat([]byte("a"), 40)
+	_, _, err = ParseTree(data, g.Rule("s"), WithMaxSteps(1000))
+	equals("ParseTree", t, true, errors.Is(err, ErrAborted))
+	parsed, remaining := Parse(data, g.Rule("s"), WithMaxSteps(1000))
+	sliceEquals("Parse", t, []byte(""), parsed)
+	sliceEquals("Parse", t, data, remaining)
+	records, errs := ParseRecords(data, g.Rule("s"), NewByteFinder('b'), WithMaxSteps(1000))
+	equals("ParseRecords", t, 0, len(records))
+	equals("ParseRecords", t, 1, len(errs))
+	equals("ParseRecords", t, true, errors.Is(errs[0], ErrAborted))
+
+	// The repetitions are recalculated without rules.
+	// 2*( 2*[ %x61-62 ] ) tries all the ways to put the empty repetitions.
+	repetition := NewLazyRepetitionMinFinder(2, NewVariableRepetitionMinFinder(2, NewOptionalSequenceFinder(NewValueRangeAlternativesFinder('a', 'b'))))
+	_, _, err = ParseContext(context.Background(), []byte("bababbbbbac"), repetition, WithLongestMatch(), WithMaxSteps(1000))
+	equals("repetition", t, "abnfp: parse aborted: more than 1000 steps", fmt.Sprint(err))
+}
+
+func TestWithContext(t *testing.T) {
+	g, err := CompileGrammar([]byte("s = 1*a\na = \"a\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	parsed, remaining, err := ParseContext(ctx, []byte("aa"), g.Rule("s"))
+	sliceEquals("ParseContext", t, []byte("aa"), parsed)
+	sliceEquals("ParseContext", t, []byte(""), remaining)
+	equals("ParseContext", t, nil, err)
+
+	cancel()
+	parsed, remaining, err = ParseContext(ctx, []byte("aa"), g.Rule("s"))
+	sliceEquals("ParseContext canceled", t, []byte(""), parsed)
+	sliceEquals("ParseContext canceled", t, []byte("aa"), remaining)
+	equals("ParseContext canceled", t, true, errors.Is(err, ErrAborted))
+	equals("ParseContext canceled", t, true, errors.Is(err, context.Canceled))
+	equals("ParseContext canceled", t, "abnfp: parse aborted: context canceled", err.Error())
+
+	_, _, err = ParseCaptures([]byte("aa"), g.Rule("s"), WithContext(ctx))
+	equals("ParseCaptures canceled", t, true, errors.Is(err, context.Canceled))
+	_, _, err = ParseValue([]byte("aa"), g.Rule("s"), WithContext(ctx))
+	equals("ParseValue canceled", t, true, errors.Is(err, context.Canceled))
+
+	// The Finders without rules and backtracking are also aborted.
+	for _, finder := range []Finder{
+		NewBytesFinder([]byte("aa")),
+		NewConcatenationFinder([]Finder{NewByteFinder('a'), NewByteFinder('a')}),
+	} {
+		parsed, remaining, err = ParseContext(ctx, []byte("aa"), finder)
+		sliceEquals("ParseContext canceled without rules", t, []byte(""), parsed)
+		sliceEquals("ParseContext canceled without rules", t, []byte("aa"), remaining)
+		equals("ParseContext canceled without rules", t, "abnfp: parse aborted: context canceled", fmt.Sprint(err))
+	}
+	result := ParseAt([]byte("aa"), 0, NewBytesFinder([]byte("aa")), WithContext(ctx))
+	equals("ParseAt canceled without rules", t, true, errors.Is(result.Err, context.Canceled))
+}
+
+func TestWithMaxDepth(t *testing.T) {
+	type TestCase struct {
+		testName          string
+		data              []byte
+		maxDepth          int
+		expectedParsed    []byte
+		expectedRemaining []byte
+		expectedErr       string
+	}
+
+	g, err := CompileGrammar([]byte("comment = \"(\" *( ctext / comment ) \")\"\nctext = %x61-7A\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []TestCase{
+		{
+			testName:          "data: []byte(\"(a(b))\"), parse in the depth",
+			data:              []byte("(a(b))"),
+			maxDepth:          3,
+			expectedParsed:    []byte("(a(b))"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName:          "data: []byte(\"(((a)))\"), abort in the depth",
+			data:              []byte("(((a)))"),
+			maxDepth:          3,
+			expectedParsed:    []byte(""),
+			expectedRemaining: []byte("(((a)))"),
+			expectedErr:       "abnfp: parse aborted: more than 3 nested rules",
+		},
+		{
+			testName:          "data: []byte(\"((((((...\"), abort in the depth",
+			data:              bytes.Repeat([]byte("("), 100000),
+			maxDepth:          1000,
+			expectedParsed:    []byte(""),
+			expectedRemaining: bytes.Repeat([]byte("("), 100000),
+			expectedErr:       "abnfp: parse aborted: more than 1000 nested rules",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			parsed, remaining, err := ParseContext(context.Background(), testCase.data, g.Rule("comment"), WithMaxDepth(testCase.maxDepth))
+			sliceEquals(testCase.testName, t, testCase.expectedParsed, parsed)
+			sliceEquals(testCase.testName, t, testCase.expectedRemaining, remaining)
+			if testCase.expectedErr == "" {
+				equals(testCase.testName, t, nil, err)
+				return
+			}
+			if err == nil {
+				t.Fatalf("%v: expected err: %v, actual: nil", testCase.testName, testCase.expectedErr)
+			}
+			equals(testCase.testName, t, true, errors.Is(err, ErrAborted))
+			equals(testCase.testName, t, testCase.expectedErr, err.Error())
+		})
+	}
+
+	// The depth is restored after the rules are exited.
+	records, errs := ParseRecords([]byte("((a))((b))"), g.Rule("comment"), NewByteFinder(')'), WithMaxDepth(3))
+	equals("ParseRecords", t, 2, len(records))
+	equals("ParseRecords", t, 0, len(errs))
+}
@@ -0,0 +1,32 @@
+package abnfp
+
+// PossessiveFinder finds the same syntax as its child Finder, but never finds
+// other data when it is recalculated, like the atomic groups of regular
+// expressions. e.g. Possessive(1*ALPHA) finds all of "abc", and never gives
+// back "c" to the following Finders.
+// It keeps the ambiguous syntax from trying all of its choices when the
+// following syntax is not found.
+
+type PossessiveFinder struct {
+	childFinder Finder
+}
+
+func (finder *PossessiveFinder) Find(data []byte) (found bool, end int) {
+	return finder.childFinder.Find(data)
+}
+
+func (finder PossessiveFinder) Copy() Finder {
+	return &PossessiveFinder{childFinder: finder.childFinder.Copy()}
+}
+
+func (finder *PossessiveFinder) foundChildren(end int) []foundChild {
+	return []foundChild{{finder: finder.childFinder, start: 0, end: end}}
+}
+
+func (finder *PossessiveFinder) setParser(p *parser) {
+	setParser(finder.childFinder, p)
+}
+
+func NewPossessiveFinder(finder Finder) *PossessiveFinder {
+	return &PossessiveFinder{childFinder: finder.Copy()}
+}
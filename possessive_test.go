@@ -0,0 +1,66 @@
+package abnfp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPossessiveFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"aab\"), find Possessive(1*a)",
+			data:          []byte("aab"),
+			finder:        NewPossessiveFinder(NewVariableRepetitionMinFinder(1, NewByteFinder('a'))),
+			expectedFound: true,
+			expectedEnd:   2,
+		},
+		{
+			testName: "data: []byte(\"aa\"), find Possessive(1*a) a",
+			data:     []byte("aa"),
+			finder: NewConcatenationFinder([]Finder{
+				NewPossessiveFinder(NewVariableRepetitionMinFinder(1, NewByteFinder('a'))),
+				NewByteFinder('a'),
+			}),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName: "data: []byte(\"aab\"), find Possessive(1*a) b",
+			data:     []byte("aab"),
+			finder: NewConcatenationFinder([]Finder{
+				NewPossessiveFinder(NewVariableRepetitionMinFinder(1, NewByteFinder('a'))),
+				NewByteFinder('b'),
+			}),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+	}
+
+	execFinderTest(tests, t)
+}
+
+// The options of the parse reach the rules inside PossessiveFinder.
+func TestPossessiveFinderParser(t *testing.T) {
+	g, err := CompileGrammar([]byte("word = 1*letter\nletter = ALPHA\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	finder := NewPossessiveFinder(g.Rule("word"))
+
+	root, _, err := ParseTree([]byte("ab"), finder)
+	equals("ParseTree", t, nil, err)
+	equals("ParseTree", t, ":0-2(word:0-2(letter:0-1 letter:1-2))", formatNode(root))
+
+	_, _, err = ParseTree([]byte("ab"), finder, WithMaxDepth(1))
+	equals("WithMaxDepth", t, true, errors.Is(err, ErrAborted))
+
+	var trace strings.Builder
+	Parse([]byte("a"), finder, WithTracer(NewIndentTracer(&trace)))
+	equals("WithTracer", t, "enter word at 0\n"+
+		"  enter letter at 0\n"+
+		"  exit letter at 0: found 0-1\n"+
+		"  enter letter at 1\n"+
+		"  exit letter at 1: not found\n"+
+		"exit word at 0: found 0-1\n", trace.String())
+}
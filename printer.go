@@ -0,0 +1,392 @@
+package abnfp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoABNF is returned when a Finder has no form in ABNF.
+var ErrNoABNF = errors.New("abnfp: no ABNF form")
+
+// The precedences of the ABNF operators. A Finder is enclosed in parentheses
+// when its precedence is lower than the one required by its parent.
+const (
+	precedenceAlternatives = iota
+	precedenceConcatenation
+	precedenceRepetition
+	precedenceElement
+)
+
+// abnfFormatter is implemented by the Finders which can be rendered in ABNF.
+type abnfFormatter interface {
+	formatABNF(p *printer) (text string, precedence int)
+}
+
+// printer renders Finders in ABNF.
+// It records the RuleFinders referred by the rendered Finders in order, and
+// the texts of the Finders which have no form in ABNF.
+type printer struct {
+	rules     []*RuleFinder
+	ruleNames map[string]bool
+	noABNF    []string
+}
+
+func newPrinter() *printer {
+	return &printer{rules: []*RuleFinder{}, ruleNames: map[string]bool{}, noABNF: []string{}}
+}
+
+// format renders finder in ABNF.
+// The Finders which are not defined in this package are rendered with their
+// String method if it returns ABNF, otherwise as a prose-val.
+func (p *printer) format(finder Finder) (text string, precedence int) {
+	switch f := finder.(type) {
+	case abnfFormatter:
+		return f.formatABNF(p)
+	case fmt.Stringer:
+		text = f.String()
+		if precedence, ok := abnfPrecedence(text); ok {
+			return text, precedence
+		}
+		return p.formatNoABNF("<" + text + ">")
+	default:
+		return p.formatNoABNF(fmt.Sprintf("<%T>", finder))
+	}
+}
+
+// formatNoABNF records text as the rendering of a Finder which has no form in
+// ABNF, and returns it as an element.
+func (p *printer) formatNoABNF(text string) (string, int) {
+	p.noABNF = append(p.noABNF, text)
+	return text, precedenceElement
+}
+
+// abnfPrecedence returns the precedence of text if all of it is ABNF.
+func abnfPrecedence(text string) (int, bool) {
+	compilers := []struct {
+		precedence int
+		compile    func(c *abnfCompiler) (ruleBuilder, error)
+	}{
+		{precedenceElement, (*abnfCompiler).compileElement},
+		{precedenceRepetition, (*abnfCompiler).compileRepetition},
+		{precedenceConcatenation, (*abnfCompiler).compileConcatenation},
+		{precedenceAlternatives, (*abnfCompiler).compileAlternation},
+	}
+	for _, compiler := range compilers {
+		c := &abnfCompiler{data: []byte(text)}
+		if _, err := compiler.compile(c); err == nil && c.eof() {
+			return compiler.precedence, true
+		}
+	}
+	return 0, false
+}
+
+// formatAt renders finder in ABNF, and encloses it in parentheses if its
+// precedence is lower than precedence.
+func (p *printer) formatAt(finder Finder, precedence int) string {
+	text, finderPrecedence := p.format(finder)
+	if finderPrecedence < precedence {
+		return "( " + text + " )"
+	}
+	return text
+}
+
+func (p *printer) addRule(rule *RuleFinder) {
+	if p.ruleNames[rule.name] {
+		return
+	}
+	p.ruleNames[rule.name] = true
+	p.rules = append(p.rules, rule)
+}
+
+func formatByte(b byte) string {
+	return fmt.Sprintf("%02X", b)
+}
+
+func (finder ByteFinder) formatABNF(p *printer) (string, int) {
+	return "%x" + formatByte(finder.target), precedenceElement
+}
+
+func (finder ByteFinder) String() string {
+	text, _ := finder.formatABNF(newPrinter())
+	return text
+}
+
+func (finder BytesFinder) formatABNF(p *printer) (string, int) {
+	if len(finder.target) == 0 {
+		// NOTE
+		// BytesFinder never finds the empty target, so it is not the same as "".
+		return p.formatNoABNF("<empty BytesFinder>")
+	}
+	values := []string{}
+	for _, b := range finder.target {
+		values = append(values, formatByte(b))
+	}
+	return "%x" + strings.Join(values, "."), precedenceElement
+}
+
+func (finder BytesFinder) String() string {
+	text, _ := finder.formatABNF(newPrinter())
+	return text
+}
+
+// CaseInsensitiveBytesFinder is rendered as a char-val if it can be,
+// otherwise as the concatenation of the alternatives of the cases.
+func (finder CaseInsensitiveBytesFinder) formatABNF(p *printer) (string, int) {
+	if len(finder.target) == 0 {
+		return p.formatNoABNF("<empty CaseInsensitiveBytesFinder>")
+	}
+	charVal := true
+	for _, b := range finder.target {
+		charVal = charVal && b >= 0x20 && b <= 0x7e && b != '"'
+	}
+	if charVal {
+		return "\"" + string(finder.target) + "\"", precedenceElement
+	}
+	return p.format(finder.caseSensitiveFinder())
+}
+
+func (finder CaseInsensitiveBytesFinder) String() string {
+	text, _ := finder.formatABNF(newPrinter())
+	return text
+}
+
+func (finder CrLfFinder) formatABNF(p *printer) (string, int) {
+	return "CRLF", precedenceElement
+}
+
+func (finder CrLfFinder) String() string {
+	return "CRLF"
+}
+
+func (finder RuleFinder) formatABNF(p *printer) (string, int) {
+	p.addRule(&finder)
+	return finder.name, precedenceElement
+}
+
+func (finder RuleFinder) String() string {
+	return finder.name
+}
+
+func (finder ConcatenationFinder) formatABNF(p *printer) (string, int) {
+	switch len(finder.childFinders) {
+	case 0:
+		return "\"\"", precedenceElement
+	case 1:
+		return p.format(finder.childFinders[0])
+	}
+	texts := []string{}
+	for _, childFinder := range finder.childFinders {
+		texts = append(texts, p.formatAt(childFinder, precedenceConcatenation))
+	}
+	return strings.Join(texts, " "), precedenceConcatenation
+}
+
+func (finder ConcatenationFinder) String() string {
+	text, _ := finder.formatABNF(newPrinter())
+	return text
+}
+
+func (finder AlternativesFinder) formatABNF(p *printer) (string, int) {
+	return p.formatAlternatives(finder.childFinders)
+}
+
+func (finder AlternativesFinder) String() string {
+	text, _ := finder.formatABNF(newPrinter())
+	return text
+}
+
+// LongestMatchAlternativesFinder is rendered as the alternatives of the same
+// syntax, because ABNF has no syntax for the longest match.
+func (finder LongestMatchAlternativesFinder) formatABNF(p *printer) (string, int) {
+	return p.formatAlternatives(finder.childFinders)
+}
+
+func (finder LongestMatchAlternativesFinder) String() string {
+	text, _ := finder.formatABNF(newPrinter())
+	return text
+}
+
+func (p *printer) formatAlternatives(childFinders []Finder) (string, int) {
+	switch len(childFinders) {
+	case 0:
+		// NOTE
+		// AlternativesFinder without alternatives never finds the syntax.
+		// ABNF has no syntax for it.
+		return p.formatNoABNF("<empty AlternativesFinder>")
+	case 1:
+		return p.format(childFinders[0])
+	}
+	texts := []string{}
+	for _, childFinder := range childFinders {
+		texts = append(texts, p.formatAt(childFinder, precedenceAlternatives))
+	}
+	return strings.Join(texts, " / "), precedenceAlternatives
+}
+
+func (finder ValueRangeAlternativesFinder) formatABNF(p *printer) (string, int) {
+	if finder.rangeStart == finder.rangeEnd {
+		return "%x" + formatByte(finder.rangeStart), precedenceElement
+	}
+	return "%x" + formatByte(finder.rangeStart) + "-" + formatByte(finder.rangeEnd), precedenceElement
+}
+
+func (finder ValueRangeAlternativesFinder) String() string {
+	text, _ := finder.formatABNF(newPrinter())
+	return text
+}
+
+func (finder VariableRepetitionMinMaxFinder) formatABNF(p *printer) (string, int) {
+	return p.formatRepetition(finder.min, finder.max, finder.childFinder)
+}
+
+func (finder VariableRepetitionMinMaxFinder) String() string {
+	text, _ := finder.formatABNF(newPrinter())
+	return text
+}
+
+// LazyRepetitionMinMaxFinder is rendered as the repetition of the same syntax,
+// because ABNF has no syntax for the lazy repetition.
+func (finder LazyRepetitionMinMaxFinder) formatABNF(p *printer) (string, int) {
+	return p.formatRepetition(finder.min, finder.max, finder.childFinder)
+}
+
+func (finder LazyRepetitionMinMaxFinder) String() string {
+	text, _ := finder.formatABNF(newPrinter())
+	return text
+}
+
+func (p *printer) formatRepetition(min int, max int, childFinder Finder) (string, int) {
+	if min == 0 && max == 1 {
+		return "[ " + p.formatAt(childFinder, precedenceAlternatives) + " ]", precedenceElement
+	}
+	return formatRepeat(min, max) + p.formatAt(childFinder, precedenceElement), precedenceRepetition
+}
+
+// formatRepeat renders the repeat of a repetition, like "1*2".
+// max is negative if the repetition is unlimited.
+func formatRepeat(min int, max int) string {
+	if min == max {
+		return fmt.Sprint(min)
+	}
+	repeat := ""
+	if min > 0 {
+		repeat += fmt.Sprint(min)
+	}
+	repeat += "*"
+	if max >= 0 {
+		repeat += fmt.Sprint(max)
+	}
+	return repeat
+}
+
+// ActionFinder is rendered as its child Finder.
+func (finder ActionFinder) formatABNF(p *printer) (string, int) {
+	return p.format(finder.childFinder)
+}
+
+func (finder ActionFinder) String() string {
+	text, _ := finder.formatABNF(newPrinter())
+	return text
+}
+
+// CaptureFinder is rendered as its child Finder.
+func (finder CaptureFinder) formatABNF(p *printer) (string, int) {
+	return p.format(finder.childFinder)
+}
+
+func (finder CaptureFinder) String() string {
+	text, _ := finder.formatABNF(newPrinter())
+	return text
+}
+
+// PossessiveFinder is rendered as its child Finder, because ABNF has no
+// syntax for it.
+func (finder PossessiveFinder) formatABNF(p *printer) (string, int) {
+	return p.format(finder.childFinder)
+}
+
+func (finder PossessiveFinder) String() string {
+	text, _ := finder.formatABNF(newPrinter())
+	return text
+}
+
+// AndFinder, NotFinder and ExceptFinder are rendered like prose-vals, because
+// ABNF has no syntax for the lookahead and the exception. The text is not
+// re-parseable.
+func (finder AndFinder) formatABNF(p *printer) (string, int) {
+	return p.formatNoABNF("<and " + p.formatAt(finder.childFinder, precedenceElement) + ">")
+}
+
+func (finder AndFinder) String() string {
+	text, _ := finder.formatABNF(newPrinter())
+	return text
+}
+
+func (finder NotFinder) formatABNF(p *printer) (string, int) {
+	return p.formatNoABNF("<not " + p.formatAt(finder.childFinder, precedenceElement) + ">")
+}
+
+func (finder NotFinder) String() string {
+	text, _ := finder.formatABNF(newPrinter())
+	return text
+}
+
+func (finder ExceptFinder) formatABNF(p *printer) (string, int) {
+	return p.formatNoABNF("<" + p.formatAt(finder.baseFinder, precedenceElement) + " except " + p.formatAt(finder.excludedFinder, precedenceElement) + ">")
+}
+
+func (finder ExceptFinder) String() string {
+	text, _ := finder.formatABNF(newPrinter())
+	return text
+}
+
+func (finder ProseValFinder) formatABNF(p *printer) (string, int) {
+	return "<" + finder.description + ">", precedenceElement
+}
+
+func (finder ProseValFinder) String() string {
+	text, _ := finder.formatABNF(newPrinter())
+	return text
+}
+
+// FormatRules renders rules and the rules referred by them in ABNF, one rule
+// per line, like
+//
+//	comment = "(" *( ctext / comment ) ")"
+//	ctext = %x21-27 / %x2A-5B / %x5D-7E
+//
+// The rules are rendered in the order in which they are first referred.
+// Each rule is rendered only once even if it is referred many times.
+//
+// NOTE
+// The Finders which have no form in ABNF, like NotFinder, are rendered like
+// prose-vals, e.g. <not DIGIT>, so the text is not always re-parseable. Use
+// FormatABNF to get an error for them.
+func FormatRules(rules ...*RuleFinder) string {
+	text, _ := FormatABNF(rules...)
+	return text
+}
+
+// FormatABNF renders rules like FormatRules, and returns the error which wraps
+// ErrNoABNF and lists the texts of the Finders which have no form in ABNF, like
+//
+//	abnfp: no ABNF form: <not %x30-39>
+//
+// The text is re-parseable if the error is nil.
+func FormatABNF(rules ...*RuleFinder) (string, error) {
+	p := newPrinter()
+	for _, rule := range rules {
+		p.addRule(rule)
+	}
+	lines := []string{}
+	for i := 0; i < len(p.rules); i++ {
+		rule := p.rules[i]
+		definition, _ := p.format(rule.newFinder())
+		lines = append(lines, rule.name+" = "+definition+"\n")
+	}
+	if len(p.noABNF) != 0 {
+		return strings.Join(lines, ""), fmt.Errorf("%w: %v", ErrNoABNF, strings.Join(p.noABNF, ", "))
+	}
+	return strings.Join(lines, ""), nil
+}
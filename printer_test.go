@@ -0,0 +1,308 @@
+package abnfp
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type unknownFinder struct{}
+
+func (finder unknownFinder) Find(data []byte) (found bool, end int) {
+	return false, 0
+}
+
+func (finder unknownFinder) Copy() Finder {
+	return unknownFinder{}
+}
+
+// stringerFinder is the Finder defined outside of this package which has
+// String method.
+type stringerFinder struct {
+	unknownFinder
+	text string
+}
+
+func (finder stringerFinder) String() string {
+	return finder.text
+}
+
+func TestString(t *testing.T) {
+	type TestCase struct {
+		testName string
+		finder   Finder
+		expected string
+	}
+
+	tests := []TestCase{
+		{
+			testName: "ByteFinder",
+			finder:   NewByteFinder('a'),
+			expected: "%x61",
+		},
+		{
+			testName: "Copy of ByteFinder",
+			finder:   NewByteFinder('a').Copy(),
+			expected: "%x61",
+		},
+		{
+			testName: "BytesFinder",
+			finder:   NewBytesFinder([]byte("\r\n")),
+			expected: "%x0D.0A",
+		},
+		{
+			testName: "CrLfFinder",
+			finder:   NewCrLfFinder(),
+			expected: "CRLF",
+		},
+		{
+			testName: "ValueRangeAlternativesFinder",
+			finder:   NewDigitFinder(),
+			expected: "%x30-39",
+		},
+		{
+			testName: "ValueRangeAlternativesFinder of one value",
+			finder:   NewValueRangeAlternativesFinder('a', 'a'),
+			expected: "%x61",
+		},
+		{
+			testName: "RuleFinder",
+			finder:   NewRuleFinder("rule", func() Finder { return NewAlphaFinder() }),
+			expected: "rule",
+		},
+		{
+			testName: "ConcatenationFinder",
+			finder:   NewConcatenationFinder([]Finder{NewByteFinder('a'), NewByteFinder('b')}),
+			expected: "%x61 %x62",
+		},
+		{
+			testName: "ConcatenationFinder without children",
+			finder:   NewConcatenationFinder([]Finder{}),
+			expected: "\"\"",
+		},
+		{
+			testName: "AlternativesFinder",
+			finder:   NewAlphaFinder(),
+			expected: "%x41-5A / %x61-7A",
+		},
+		{
+			testName: "AlternativesFinder in ConcatenationFinder",
+			finder:   NewConcatenationFinder([]Finder{NewAlphaFinder(), NewDigitFinder()}),
+			expected: "( %x41-5A / %x61-7A ) %x30-39",
+		},
+		{
+			testName: "ConcatenationFinder in AlternativesFinder",
+			finder: NewAlternativesFinder([]Finder{
+				NewConcatenationFinder([]Finder{NewByteFinder('a'), NewByteFinder('b')}),
+				NewByteFinder('c'),
+			}),
+			expected: "%x61 %x62 / %x63",
+		},
+		{
+			testName: "LongestMatchAlternativesFinder",
+			finder: NewLongestMatchAlternativesFinder([]Finder{
+				NewByteFinder('a'),
+				NewBytesFinder([]byte("ab")),
+			}),
+			expected: "%x61 / %x61.62",
+		},
+		{
+			testName: "VariableRepetitionFinder",
+			finder:   NewVariableRepetitionFinder(NewDigitFinder()),
+			expected: "*%x30-39",
+		},
+		{
+			testName: "VariableRepetitionMinFinder",
+			finder:   NewVariableRepetitionMinFinder(1, NewDigitFinder()),
+			expected: "1*%x30-39",
+		},
+		{
+			testName: "VariableRepetitionMaxFinder",
+			finder:   NewVariableRepetitionMaxFinder(2, NewDigitFinder()),
+			expected: "*2%x30-39",
+		},
+		{
+			testName: "VariableRepetitionMinMaxFinder",
+			finder:   NewVariableRepetitionMinMaxFinder(1, 2, NewDigitFinder()),
+			expected: "1*2%x30-39",
+		},
+		{
+			testName: "SpecificRepetitionFinder",
+			finder:   NewSpecificRepetitionFinder(3, NewDigitFinder()),
+			expected: "3%x30-39",
+		},
+		{
+			testName: "OptionalSequenceFinder",
+			finder:   NewOptionalSequenceFinder(NewConcatenationFinder([]Finder{NewByteFinder('a'), NewByteFinder('b')})),
+			expected: "[ %x61 %x62 ]",
+		},
+		{
+			testName: "VariableRepetitionFinder of ConcatenationFinder",
+			finder:   NewVariableRepetitionFinder(NewConcatenationFinder([]Finder{NewByteFinder('a'), NewByteFinder('b')})),
+			expected: "*( %x61 %x62 )",
+		},
+		{
+			testName: "VariableRepetitionFinder of VariableRepetitionFinder",
+			finder:   NewVariableRepetitionFinder(NewVariableRepetitionFinder(NewByteFinder('a'))),
+			expected: "*( *%x61 )",
+		},
+		{
+			testName: "VariableRepetitionFinder of OptionalSequenceFinder",
+			finder:   NewVariableRepetitionFinder(NewOptionalSequenceFinder(NewByteFinder('a'))),
+			expected: "*[ %x61 ]",
+		},
+		{
+			testName: "ActionFinder",
+			finder:   NewVariableRepetitionFinder(NewActionFinder(NewAlphaFinder(), nil)),
+			expected: "*( %x41-5A / %x61-7A )",
+		},
+		{
+			testName: "CaptureFinder",
+			finder:   NewCaptureFinder("digit", NewDigitFinder()),
+			expected: "%x30-39",
+		},
+		{
+			testName: "AndFinder",
+			finder:   NewConcatenationFinder([]Finder{NewAlphaFinder(), NewAndFinder(NewDigitFinder())}),
+			expected: "( %x41-5A / %x61-7A ) <and %x30-39>",
+		},
+		{
+			testName: "NotFinder",
+			finder:   NewConcatenationFinder([]Finder{NewNotFinder(NewDQuoteFinder()), NewVCharFinder()}),
+			expected: "<not %x22> %x21-7E",
+		},
+		{
+			testName: "ExceptFinder",
+			finder:   NewExceptFinder(NewVCharFinder(), NewDQuoteFinder()),
+			expected: "<%x21-7E except %x22>",
+		},
+		{
+			testName: "ProseValFinder",
+			finder:   NewConcatenationFinder([]Finder{NewProseValFinder("host name"), NewByteFinder(':')}),
+			expected: "<host name> %x3A",
+		},
+		{
+			testName: "CaseInsensitiveBytesFinder",
+			finder:   NewCaseInsensitiveBytesFinder([]byte("GET")),
+			expected: "\"GET\"",
+		},
+		{
+			testName: "CaseInsensitiveBytesFinder with DQUOTE",
+			finder:   NewCaseInsensitiveBytesFinder([]byte("a\"")),
+			expected: "( %x61 / %x41 ) %x22",
+		},
+		{
+			testName: "Finder defined outside of this package",
+			finder:   NewConcatenationFinder([]Finder{unknownFinder{}}),
+			expected: "<abnfp.unknownFinder>",
+		},
+		{
+			testName: "Finder defined outside of this package with String of element",
+			finder:   NewVariableRepetitionFinder(stringerFinder{text: "%x30-39"}),
+			expected: "*%x30-39",
+		},
+		{
+			testName: "Finder defined outside of this package with String of alternatives",
+			finder:   NewVariableRepetitionFinder(stringerFinder{text: "\"a/b\" / %x2F"}),
+			expected: "*( \"a/b\" / %x2F )",
+		},
+		{
+			testName: "Finder defined outside of this package with String of concatenation",
+			finder:   NewVariableRepetitionFinder(stringerFinder{text: "\"a\" \"b\""}),
+			expected: "*( \"a\" \"b\" )",
+		},
+		{
+			testName: "Finder defined outside of this package with String of non-ABNF",
+			finder:   NewVariableRepetitionFinder(stringerFinder{text: "a > b"}),
+			expected: "*<a > b>",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			equals(testCase.testName, t, testCase.expected, fmt.Sprint(testCase.finder))
+		})
+	}
+}
+
+func TestFormatRules(t *testing.T) {
+	// ctext   = %x21-27 / %x2A-5B / %x5D-7E
+	// comment = "(" *( ctext / comment ) ")"
+	ctext := NewRuleFinder("ctext", func() Finder {
+		return NewAlternativesFinder([]Finder{
+			NewValueRangeAlternativesFinder(0x21, 0x27),
+			NewValueRangeAlternativesFinder(0x2A, 0x5B),
+			NewValueRangeAlternativesFinder(0x5D, 0x7E),
+		})
+	})
+	var comment *RuleFinder
+	comment = NewRuleFinder("comment", func() Finder {
+		return NewConcatenationFinder([]Finder{
+			NewByteFinder('('),
+			NewVariableRepetitionFinder(NewAlternativesFinder([]Finder{ctext, comment})),
+			NewByteFinder(')'),
+		})
+	})
+	expected := "comment = %x28 *( ctext / comment ) %x29\n" +
+		"ctext = %x21-27 / %x2A-5B / %x5D-7E\n"
+	equals("FormatRules(comment)", t, expected, FormatRules(comment))
+	expected = "ctext = %x21-27 / %x2A-5B / %x5D-7E\n" +
+		"comment = %x28 *( ctext / comment ) %x29\n"
+	equals("FormatRules(ctext, comment)", t, expected, FormatRules(ctext, comment))
+}
+
+func TestFormatABNF(t *testing.T) {
+	type TestCase struct {
+		testName     string
+		rule         *RuleFinder
+		expectedText string
+		expectedErr  string
+	}
+
+	tests := []TestCase{
+		{
+			testName:     "ABNF",
+			rule:         NewRuleFinder("digits", func() Finder { return NewVariableRepetitionFinder(NewValueRangeAlternativesFinder(0x30, 0x39)) }),
+			expectedText: "digits = *%x30-39\n",
+		},
+		{
+			testName: "NotFinder and ExceptFinder",
+			rule: NewRuleFinder("word", func() Finder {
+				return NewConcatenationFinder([]Finder{
+					NewNotFinder(NewValueRangeAlternativesFinder(0x30, 0x39)),
+					NewExceptFinder(NewValueRangeAlternativesFinder(0x21, 0x7E), NewByteFinder('>')),
+				})
+			}),
+			expectedText: "word = <not %x30-39> <%x21-7E except %x3E>\n",
+			expectedErr:  "abnfp: no ABNF form: <not %x30-39>, <%x21-7E except %x3E>",
+		},
+		{
+			testName:     "Finder defined outside of this package",
+			rule:         NewRuleFinder("unknown", func() Finder { return unknownFinder{} }),
+			expectedText: "unknown = <abnfp.unknownFinder>\n",
+			expectedErr:  "abnfp: no ABNF form: <abnfp.unknownFinder>",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			text, err := FormatABNF(testCase.rule)
+			equals(testCase.testName, t, testCase.expectedText, text)
+			equals(testCase.testName, t, testCase.expectedText, FormatRules(testCase.rule))
+			actualErr := ""
+			if err != nil {
+				actualErr = err.Error()
+				if !errors.Is(err, ErrNoABNF) {
+					t.Errorf("%v: expected ErrNoABNF, actual: %v", testCase.testName, err)
+				}
+			}
+			equals(testCase.testName, t, testCase.expectedErr, actualErr)
+			if err == nil {
+				// The text without error is re-parseable.
+				if err := NewGrammar().Compile([]byte(text)); err != nil {
+					t.Errorf("%v: Compile: %v", testCase.testName, err)
+				}
+			}
+		})
+	}
+}
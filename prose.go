@@ -0,0 +1,133 @@
+package abnfp
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RFC5234 - 4. ABNF Definition of ABNF
+//
+//  prose-val = "<" *(%x20-3D / %x3F-7E) ">"
+//          ; bracketed string of SP and VCHAR
+//          ;  without angles
+//          ; prose description, to be used as
+//          ;  last resort
+//
+// ProseValFinder is the placeholder of a prose-val. It finds nothing until a
+// Finder is bound to it with WithProseVal.
+
+type ProseValFinder struct {
+	description string
+	finder      Finder
+	parser      *parser
+}
+
+func (finder *ProseValFinder) Find(data []byte) (found bool, end int) {
+	finder.finder = nil
+	bound := finder.parser.proseVal(finder.description)
+	if bound == nil {
+		return false, 0
+	}
+	finder.finder = bound.Copy()
+	setParser(finder.finder, finder.parser)
+	return finder.finder.Find(data)
+}
+
+func (finder ProseValFinder) Copy() Finder {
+	return &ProseValFinder{description: finder.description, parser: finder.parser}
+}
+
+func (finder *ProseValFinder) Recalculate(data []byte) (found bool, end int) {
+	if variableFinder, ok := finder.finder.(VariableFinder); ok {
+		return variableFinder.Recalculate(data)
+	}
+	return false, 0
+}
+
+func (finder *ProseValFinder) foundChildren(end int) []foundChild {
+	if finder.finder == nil {
+		return nil
+	}
+	return []foundChild{{finder: finder.finder, start: 0, end: end}}
+}
+
+// setParser doesn't set p to the bound Finder, because it is copied at each
+// Find.
+func (finder *ProseValFinder) setParser(p *parser) {
+	finder.parser = p
+}
+
+func (finder *ProseValFinder) Description() string {
+	return finder.description
+}
+
+func NewProseValFinder(description string) *ProseValFinder {
+	return &ProseValFinder{description: description}
+}
+
+// proseVal returns the Finder bound to the prose-vals whose description is
+// description, or nil if it is not bound.
+func (p *parser) proseVal(description string) Finder {
+	if p == nil {
+		return nil
+	}
+	return p.proseVals[description]
+}
+
+// FinderFunc is an adapter to use a function as a Finder, e.g. to bind a Go
+// function to a prose-val.
+// The function must not hold a state, because the copies of FinderFunc share it.
+type FinderFunc func(data []byte) (found bool, end int)
+
+func (f FinderFunc) Find(data []byte) (found bool, end int) {
+	return f(data)
+}
+
+func (f FinderFunc) Copy() Finder {
+	return f
+}
+
+var ErrUnboundProseVal = errors.New("abnfp: unbound prose-val")
+
+// CheckProseVals returns ErrUnboundProseVal listing the descriptions of the
+// prose-vals in finder which are not bound by opts, like
+//
+//	abnfp: unbound prose-val: <host name>, <port number>
+//
+// It returns nil if all of them are bound.
+func CheckProseVals(finder Finder, opts ...Option) error {
+	p := &parser{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	unbound := map[string]bool{}
+	walkFinders(finder, func(finder Finder) {
+		if proseVal, ok := finder.(*ProseValFinder); ok && p.proseVal(proseVal.description) == nil {
+			unbound[proseVal.description] = true
+		}
+	})
+	if len(unbound) == 0 {
+		return nil
+	}
+	descriptions := []string{}
+	for description := range unbound {
+		descriptions = append(descriptions, "<"+description+">")
+	}
+	sort.Strings(descriptions)
+	return fmt.Errorf("%w: %v", ErrUnboundProseVal, strings.Join(descriptions, ", "))
+}
+
+// notFound returns ErrNotFound of the parse of finder with opts. If finder
+// has the prose-vals not bound by opts, the error also wraps
+// ErrUnboundProseVal listing them, because they might be why the syntax is not
+// found, like
+//
+//	abnfp: syntax not found: abnfp: unbound prose-val: <host name>
+func notFound(finder Finder, opts []Option) error {
+	if err := CheckProseVals(finder, opts...); err != nil {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	return ErrNotFound
+}
@@ -0,0 +1,229 @@
+package abnfp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestProseValFinder(t *testing.T) {
+	type TestCase struct {
+		testName          string
+		data              []byte
+		finder            Finder
+		opts              []Option
+		expectedParsed    []byte
+		expectedRemaining []byte
+	}
+
+	digits := FinderFunc(func(data []byte) (bool, int) {
+		end := 0
+		for end < len(data) && '0' <= data[end] && data[end] <= '9' {
+			end++
+		}
+		return end > 0, end
+	})
+
+	tests := []TestCase{
+		{
+			testName:          "data: []byte(\"a\"), parse unbound <letter>",
+			data:              []byte("a"),
+			finder:            NewProseValFinder("letter"),
+			expectedParsed:    []byte(""),
+			expectedRemaining: []byte("a"),
+		},
+		{
+			testName:          "data: []byte(\"a\"), parse <letter> bound to ALPHA",
+			data:              []byte("a"),
+			finder:            NewProseValFinder("letter"),
+			opts:              []Option{WithProseVal("letter", NewAlphaFinder())},
+			expectedParsed:    []byte("a"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName:          "data: []byte(\"a\"), parse <letter> bound to the other description",
+			data:              []byte("a"),
+			finder:            NewProseValFinder("letter"),
+			opts:              []Option{WithProseVal("digit", NewAlphaFinder())},
+			expectedParsed:    []byte(""),
+			expectedRemaining: []byte("a"),
+		},
+		{
+			testName:          "data: []byte(\"123a\"), parse <digits> bound to FinderFunc",
+			data:              []byte("123a"),
+			finder:            NewProseValFinder("digits"),
+			opts:              []Option{WithProseVal("digits", digits)},
+			expectedParsed:    []byte("123"),
+			expectedRemaining: []byte("a"),
+		},
+		//
+		// NOTE
+		// In this test case, <letters> finds "ab" first, then it is recalculated to find "a".
+		//
+		{
+			testName: "data: []byte(\"ab\"), parse <letters> \"b\"",
+			data:     []byte("ab"),
+			finder: NewConcatenationFinder([]Finder{
+				NewProseValFinder("letters"),
+				NewByteFinder('b'),
+			}),
+			opts:              []Option{WithProseVal("letters", NewVariableRepetitionFinder(NewAlphaFinder()))},
+			expectedParsed:    []byte("ab"),
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName: "data: []byte(\"1a\"), parse rule which refers to <digits>",
+			data:     []byte("1a"),
+			finder: NewRuleFinder("number", func() Finder {
+				return NewConcatenationFinder([]Finder{NewProseValFinder("digits"), NewAlphaFinder()})
+			}),
+			opts:              []Option{WithProseVal("digits", digits)},
+			expectedParsed:    []byte("1a"),
+			expectedRemaining: []byte(""),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			actualParsed, actualRemaining := Parse(testCase.data, testCase.finder, testCase.opts...)
+			sliceEquals(testCase.testName, t, testCase.expectedParsed, actualParsed)
+			sliceEquals(testCase.testName, t, testCase.expectedRemaining, actualRemaining)
+		})
+	}
+}
+
+func TestProseValFinderCapture(t *testing.T) {
+	finder := NewProseValFinder("host")
+	host := NewCaptureFinder("host", NewVariableRepetitionMinFinder(1, NewAlphaFinder()))
+	captures, remaining, err := ParseCaptures([]byte("example:"), finder, WithProseVal("host", host))
+	equals("err", t, nil, err)
+	equals("captures", t, 1, len(captures))
+	equals("capture", t, "example", string(captures[0].Value))
+	sliceEquals("remaining", t, []byte(":"), remaining)
+}
+
+func TestCheckProseVals(t *testing.T) {
+	type TestCase struct {
+		testName    string
+		finder      Finder
+		opts        []Option
+		expectedErr string
+	}
+
+	var list *RuleFinder
+	list = NewRuleFinder("list", func() Finder {
+		return NewConcatenationFinder([]Finder{
+			NewProseValFinder("item"),
+			NewVariableRepetitionFinder(NewConcatenationFinder([]Finder{NewByteFinder(','), list})),
+			NewNotFinder(NewProseValFinder("end")),
+		})
+	})
+
+	tests := []TestCase{
+		{
+			testName:    "no prose-vals",
+			finder:      NewAlphaFinder(),
+			expectedErr: "",
+		},
+		{
+			testName:    "unbound prose-vals in recursive rule",
+			finder:      list,
+			expectedErr: "abnfp: unbound prose-val: <end>, <item>",
+		},
+		{
+			testName:    "a bound prose-val",
+			finder:      list,
+			opts:        []Option{WithProseVal("item", NewAlphaFinder())},
+			expectedErr: "abnfp: unbound prose-val: <end>",
+		},
+		{
+			testName:    "all bound prose-vals",
+			finder:      list,
+			opts:        []Option{WithProseVal("item", NewAlphaFinder()), WithProseVal("end", NewCrLfFinder())},
+			expectedErr: "",
+		},
+		{
+			testName:    "the same prose-vals",
+			finder:      NewExceptFinder(NewProseValFinder("word"), NewProseValFinder("word")),
+			expectedErr: "abnfp: unbound prose-val: <word>",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			err := CheckProseVals(testCase.finder, testCase.opts...)
+			if testCase.expectedErr == "" {
+				equals(testCase.testName, t, nil, err)
+				return
+			}
+			if err == nil {
+				t.Fatalf("%v: expected err: %v, actual: nil", testCase.testName, testCase.expectedErr)
+			}
+			equals(testCase.testName, t, true, errors.Is(err, ErrUnboundProseVal))
+			equals(testCase.testName, t, testCase.expectedErr, err.Error())
+		})
+	}
+}
+
+// The parses which don't find the syntax report the unbound prose-vals.
+func TestParseUnboundProseVal(t *testing.T) {
+	// authority = <host name> [ ":" port ]
+	// port      = <port number>
+	port := NewRuleFinder("port", func() Finder { return NewProseValFinder("port number") })
+	authority := NewRuleFinder("authority", func() Finder {
+		return NewConcatenationFinder([]Finder{
+			NewProseValFinder("host name"),
+			NewOptionalSequenceFinder(NewConcatenationFinder([]Finder{NewByteFinder(':'), port})),
+		})
+	})
+	data := []byte("example.com:80")
+	host := WithProseVal("host name", NewVariableRepetitionMinFinder(1, NewAlphaFinder()))
+	expectedErr := "abnfp: syntax not found: abnfp: unbound prose-val: <host name>, <port number>"
+
+	parses := []struct {
+		name  string
+		parse func(opts ...Option) error
+	}{
+		{"ParseContext", func(opts ...Option) error {
+			_, _, err := ParseContext(context.Background(), data, authority, opts...)
+			return err
+		}},
+		{"ParseAt", func(opts ...Option) error {
+			return ParseAt(data, 0, authority, opts...).Err
+		}},
+		{"ParseTree", func(opts ...Option) error {
+			_, _, err := ParseTree(data, authority, opts...)
+			return err
+		}},
+		{"ParseValue", func(opts ...Option) error {
+			_, _, err := ParseValue(data, authority, opts...)
+			return err
+		}},
+		{"ParseCaptures", func(opts ...Option) error {
+			_, _, err := ParseCaptures(data, authority, opts...)
+			return err
+		}},
+		{"ParseRecords", func(opts ...Option) error {
+			_, errs := ParseRecords(data, authority, NewByteFinder(':'), opts...)
+			return errs[len(errs)-1]
+		}},
+	}
+	for _, parse := range parses {
+		err := parse.parse()
+		equals(parse.name, t, expectedErr, fmt.Sprint(err))
+		equals(parse.name, t, true, errors.Is(err, ErrNotFound))
+		equals(parse.name, t, true, errors.Is(err, ErrUnboundProseVal))
+	}
+
+	// The syntax found without the unbound prose-val is not an error.
+	_, remaining, err := ParseTree(data, authority, host)
+	equals("found", t, nil, err)
+	sliceEquals("found", t, []byte(".com:80"), remaining)
+	// The syntax not found for other reasons is reported with the unbound
+	// prose-val, which might be the reason.
+	_, _, err = ParseTree([]byte("1"), authority, host)
+	equals("not found", t, "abnfp: syntax not found: abnfp: unbound prose-val: <port number>", fmt.Sprint(err))
+	_, _, err = ParseTree([]byte("1"), authority, host, WithProseVal("port number", NewDigitFinder()))
+	equals("not found", t, ErrNotFound, err)
+}
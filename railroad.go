@@ -0,0 +1,244 @@
+package abnfp
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// The sizes of the railroad diagrams in pixels.
+const (
+	railroadCharWidth  = 8
+	railroadBoxHeight  = 24
+	railroadPadding    = 10
+	railroadGap        = 10
+	railroadRail       = 20
+	railroadLabel      = 14
+	railroadMargin     = 20
+	railroadTitle      = 20
+	railroadEndMarker  = 10
+	railroadTextOffset = 4
+)
+
+// railroadBox is the layout of a syntaxNode.
+// The track enters the box at (0, baseline) and exits at (width, baseline).
+type railroadBox struct {
+	width    int
+	height   int
+	baseline int
+	draw     func(b *strings.Builder, x int, y int)
+}
+
+func drawLine(b *strings.Builder, x1 int, y1 int, x2 int, y2 int) {
+	if x1 == x2 && y1 == y2 {
+		return
+	}
+	fmt.Fprintf(b, "<line x1=\"%v\" y1=\"%v\" x2=\"%v\" y2=\"%v\"/>\n", x1, y1, x2, y2)
+}
+
+func newRailroadEmptyBox() railroadBox {
+	return railroadBox{draw: func(b *strings.Builder, x int, y int) {}}
+}
+
+// newRailroadTextBox returns the box of a terminal or a rule. The box of a rule
+// is square, and the box of a terminal is rounded.
+func newRailroadTextBox(text string, class string) railroadBox {
+	width := len(text)*railroadCharWidth + 2*railroadPadding
+	radius := 0
+	if class == "terminal" {
+		radius = railroadBoxHeight / 2
+	}
+	return railroadBox{
+		width:    width,
+		height:   railroadBoxHeight,
+		baseline: railroadBoxHeight / 2,
+		draw: func(b *strings.Builder, x int, y int) {
+			fmt.Fprintf(b, "<rect class=\"%v\" x=\"%v\" y=\"%v\" width=\"%v\" height=\"%v\" rx=\"%v\"/>\n",
+				class, x, y, width, railroadBoxHeight, radius)
+			fmt.Fprintf(b, "<text x=\"%v\" y=\"%v\" text-anchor=\"middle\">%v</text>\n",
+				x+width/2, y+railroadBoxHeight/2+railroadTextOffset, html.EscapeString(text))
+		},
+	}
+}
+
+// newRailroadSequenceBox returns the box of the boxes connected from left to right.
+func newRailroadSequenceBox(boxes []railroadBox) railroadBox {
+	if len(boxes) == 0 {
+		return newRailroadEmptyBox()
+	}
+	width, above, below := 0, 0, 0
+	for i, box := range boxes {
+		if i != 0 {
+			width += railroadGap
+		}
+		width += box.width
+		above = max(above, box.baseline)
+		below = max(below, box.height-box.baseline)
+	}
+	return railroadBox{
+		width:    width,
+		height:   above + below,
+		baseline: above,
+		draw: func(b *strings.Builder, x int, y int) {
+			for i, box := range boxes {
+				if i != 0 {
+					drawLine(b, x, y+above, x+railroadGap, y+above)
+					x += railroadGap
+				}
+				box.draw(b, x, y+above-box.baseline)
+				x += box.width
+			}
+		},
+	}
+}
+
+// newRailroadChoiceBox returns the box of the boxes stacked from top to bottom.
+// The track enters and exits the box at the first box.
+func newRailroadChoiceBox(boxes []railroadBox) railroadBox {
+	width, height := 0, 0
+	for i, box := range boxes {
+		if i != 0 {
+			height += railroadGap
+		}
+		width = max(width, box.width)
+		height += box.height
+	}
+	width += 2 * railroadRail
+	baseline := boxes[0].baseline
+	return railroadBox{
+		width:    width,
+		height:   height,
+		baseline: baseline,
+		draw: func(b *strings.Builder, x int, y int) {
+			left, right := x+railroadRail/2, x+width-railroadRail/2
+			drawLine(b, x, y+baseline, left, y+baseline)
+			drawLine(b, right, y+baseline, x+width, y+baseline)
+			top := y
+			for _, box := range boxes {
+				track := top + box.baseline
+				drawLine(b, left, y+baseline, left, track)
+				drawLine(b, right, y+baseline, right, track)
+				drawLine(b, left, track, x+railroadRail, track)
+				box.draw(b, x+railroadRail, top)
+				drawLine(b, x+railroadRail+box.width, track, right, track)
+				top += box.height + railroadGap
+			}
+		},
+	}
+}
+
+// newRailroadLoopBox returns the box of the box with the track going back
+// from its exit to its entry under it. label is written on the track.
+func newRailroadLoopBox(box railroadBox, label string) railroadBox {
+	width := box.width + 2*railroadRail
+	height := box.height + railroadGap + railroadLabel
+	return railroadBox{
+		width:    width,
+		height:   height,
+		baseline: box.baseline,
+		draw: func(b *strings.Builder, x int, y int) {
+			left, right := x+railroadRail/2, x+width-railroadRail/2
+			track, back := y+box.baseline, y+box.height+railroadGap
+			drawLine(b, x, track, x+railroadRail, track)
+			box.draw(b, x+railroadRail, y)
+			drawLine(b, x+railroadRail+box.width, track, x+width, track)
+			drawLine(b, right, track, right, back)
+			drawLine(b, right, back, left, back)
+			drawLine(b, left, back, left, track)
+			fmt.Fprintf(b, "<text class=\"label\" x=\"%v\" y=\"%v\" text-anchor=\"middle\">%v</text>\n",
+				x+width/2, back+railroadLabel-2, html.EscapeString(label))
+		},
+	}
+}
+
+func newRailroadBox(node *syntaxNode) railroadBox {
+	switch node.kind {
+	case syntaxRule:
+		return newRailroadTextBox(node.label, "rule")
+	case syntaxConcatenation:
+		boxes := []railroadBox{}
+		for _, child := range node.children {
+			boxes = append(boxes, newRailroadBox(child))
+		}
+		return newRailroadSequenceBox(boxes)
+	case syntaxAlternatives:
+		boxes := []railroadBox{}
+		for _, child := range node.children {
+			boxes = append(boxes, newRailroadBox(child))
+		}
+		return newRailroadChoiceBox(boxes)
+	case syntaxRepetition:
+		box := newRailroadBox(node.children[0])
+		if node.max == 0 {
+			return newRailroadEmptyBox()
+		}
+		if node.min > 1 || node.max != 1 {
+			box = newRailroadLoopBox(box, node.label)
+		}
+		if node.min == 0 {
+			// The track skipping the repetition is on the top.
+			box = newRailroadChoiceBox([]railroadBox{newRailroadEmptyBox(), box})
+		}
+		return box
+	}
+	return newRailroadTextBox(node.label, "terminal")
+}
+
+// WriteRailroadSVG writes the railroad diagram of finder to w as an SVG image.
+// The diagrams of the rules referred by finder follow it with the names of
+// the rules.
+func WriteRailroadSVG(w io.Writer, finder Finder) error {
+	root, rules := newSyntaxDefinitions(finder)
+	type diagram struct {
+		title string
+		box   railroadBox
+	}
+	diagrams := []diagram{}
+	if root.kind != syntaxRule {
+		diagrams = append(diagrams, diagram{box: newRailroadBox(root)})
+	}
+	for _, rule := range rules {
+		diagrams = append(diagrams, diagram{title: rule.name, box: newRailroadBox(rule.definition)})
+	}
+
+	width, height := 0, railroadMargin
+	for _, d := range diagrams {
+		width = max(width, d.box.width+2*railroadEndMarker)
+		if d.title != "" {
+			height += railroadTitle
+		}
+		height += d.box.height + railroadMargin
+	}
+	width += 2 * railroadMargin
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%v\" height=\"%v\" viewBox=\"0 0 %v %v\">\n",
+		width, height, width, height)
+	b.WriteString("<style>\n" +
+		"line { stroke: black; stroke-width: 2; }\n" +
+		"rect { fill: white; stroke: black; stroke-width: 2; }\n" +
+		"text { font-family: monospace; font-size: 14px; }\n" +
+		"text.title { font-weight: bold; }\n" +
+		"text.label { font-size: 10px; }\n" +
+		"</style>\n")
+	y := railroadMargin
+	for _, d := range diagrams {
+		if d.title != "" {
+			fmt.Fprintf(b, "<text class=\"title\" x=\"%v\" y=\"%v\">%v</text>\n",
+				railroadMargin, y+railroadTitle-railroadTextOffset*2, html.EscapeString(d.title))
+			y += railroadTitle
+		}
+		x, track := railroadMargin, y+d.box.baseline
+		drawLine(b, x, track-railroadEndMarker/2, x, track+railroadEndMarker/2)
+		drawLine(b, x, track, x+railroadEndMarker, track)
+		d.box.draw(b, x+railroadEndMarker, y)
+		x += railroadEndMarker + d.box.width
+		drawLine(b, x, track, x+railroadEndMarker, track)
+		drawLine(b, x+railroadEndMarker, track-railroadEndMarker/2, x+railroadEndMarker, track+railroadEndMarker/2)
+		y += d.box.height + railroadMargin
+	}
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
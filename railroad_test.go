@@ -0,0 +1,90 @@
+package abnfp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestWriteRailroadSVG(t *testing.T) {
+	type TestCase struct {
+		testName      string
+		finder        Finder
+		expectedTexts []string
+	}
+
+	// list = item *( "," item )
+	// item = 1*DIGIT / "(" list ")"
+	var list *RuleFinder
+	item := NewRuleFinder("item", func() Finder {
+		return NewAlternativesFinder([]Finder{
+			NewVariableRepetitionMinFinder(1, NewDigitFinder()),
+			NewConcatenationFinder([]Finder{NewByteFinder('('), list, NewByteFinder(')')}),
+		})
+	})
+	list = NewRuleFinder("list", func() Finder {
+		return NewConcatenationFinder([]Finder{
+			item,
+			NewVariableRepetitionFinder(NewConcatenationFinder([]Finder{NewByteFinder(','), item})),
+		})
+	})
+
+	tests := []TestCase{
+		{
+			testName:      "ByteFinder",
+			finder:        NewByteFinder('<'),
+			expectedTexts: []string{"%x3C"},
+		},
+		{
+			testName: "VariableRepetitionMinMaxFinder",
+			finder: NewConcatenationFinder([]Finder{
+				NewVariableRepetitionMinMaxFinder(2, 3, NewByteFinder('a')),
+				NewOptionalSequenceFinder(NewByteFinder('b')),
+			}),
+			expectedTexts: []string{"%x61", "2*3", "%x62"},
+		},
+		{
+			testName: "recursive RuleFinders",
+			finder:   list,
+			expectedTexts: []string{
+				"list", "item", "%x2C", "item", "*",
+				"item", "%x30-39", "1*", "%x28", "list", "%x29",
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := WriteRailroadSVG(buf, testCase.finder)
+			equals(testCase.testName, t, nil, err)
+
+			// The SVG must be well-formed, and have the texts in order.
+			texts := []string{}
+			decoder := xml.NewDecoder(buf)
+			inText := false
+			for {
+				token, err := decoder.Token()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("%v: %v", testCase.testName, err)
+				}
+				switch tok := token.(type) {
+				case xml.StartElement:
+					inText = tok.Name.Local == "text"
+				case xml.EndElement:
+					inText = false
+				case xml.CharData:
+					if inText {
+						texts = append(texts, string(tok))
+					}
+				}
+			}
+			sliceEquals(testCase.testName, t, testCase.expectedTexts, texts)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package abnfp
+
+import "fmt"
+
+// RecordError is the error of the data skipped by ParseRecords, because the
+// Finder of the records couldn't find a record there.
+// Span is the offsets of the skipped data.
+type RecordError struct {
+	Span
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("%v: skipped %v-%v", ErrNotFound, e.Start, e.End)
+}
+
+func (e *RecordError) Unwrap() error {
+	return ErrNotFound
+}
+
+// ParseRecords finds the records of finder one after another from the
+// beginning of data to the end, and returns the trees of the records found, as
+// ParseTree does.
+// When finder can't find a record, the data is skipped up to the end of the
+// next syntax found by sync, like CRLF, and the parse continues from there. The
+// skipped data is reported as RecordError in errs. If sync finds nothing, the
+// rest of the data is skipped.
+// A record must not be empty, otherwise the parse would not go forward, so
+// finder finding no data is treated as not finding the record.
+//
+// The offsets of the Nodes and the RecordErrors are the offsets in data.
+// If the parse is aborted by WithContext or WithMaxSteps, the error which
+// wraps ErrAborted is the last of errs. If finder can't find a record and it
+// has the prose-vals not bound, the parse stops there, and the error which
+// wraps ErrNotFound and ErrUnboundProseVal is the last of errs.
+func ParseRecords(data []byte, finder Finder, sync Finder, opts ...Option) (records []*Node, errs []error) {
+	p := newParser(data, opts)
+	finder = p.prepare(finder)
+	sync = p.prepare(sync)
+	records = []*Node{}
+	errs = []error{}
+	for start := 0; start < len(data); {
+		found, end, err := p.find(finder, data[start:])
+		if err != nil {
+			return records, append(errs, err)
+		}
+		if found && end > 0 {
+			records = append(records, newTree(finder, start, start+end))
+			start += end
+			continue
+		}
+		if len(errs) == 0 {
+			// NOTE
+			// The prose-vals are checked only at the first record not found,
+			// because the walk of a large grammar takes time.
+			if err := notFound(finder, opts); err != ErrNotFound {
+				return records, append(errs, err)
+			}
+		}
+		skipped, err := skipTo(p, sync, data, start)
+		if err != nil {
+			return records, append(errs, err)
+		}
+		errs = append(errs, &RecordError{Span: Span{Start: start, End: skipped}})
+		start = skipped
+	}
+	return records, errs
+}
+
+// skipTo returns the end of the first non-empty syntax found by sync in data
+// after start, or the length of data if sync finds nothing.
+func skipTo(p *parser, sync Finder, data []byte, start int) (int, error) {
+	for i := start; i < len(data); i++ {
+		found, end, err := p.find(sync, data[i:])
+		if err != nil {
+			return 0, err
+		}
+		if found && end > 0 {
+			return i + end, nil
+		}
+	}
+	return len(data), nil
+}
@@ -0,0 +1,92 @@
+package abnfp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseRecords(t *testing.T) {
+	type TestCase struct {
+		testName        string
+		data            []byte
+		finder          Finder
+		sync            Finder
+		expectedRecords []string
+		expectedErrs    []string
+	}
+
+	g, err := CompileGrammar([]byte("field = name \":\" value CRLF\n" +
+		"name = 1*ALPHA\n" +
+		"value = *VCHAR\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []TestCase{
+		{
+			testName:        "data: []byte(\"a:1\\r\\nb:2\\r\\n\"), all records found",
+			data:            []byte("a:1\r\nb:2\r\n"),
+			finder:          g.Rule("field"),
+			sync:            NewCrLfFinder(),
+			expectedRecords: []string{"field:0-5(name:0-1 value:2-3)", "field:5-10(name:5-6 value:7-8)"},
+			expectedErrs:    []string{},
+		},
+		{
+			testName:        "data: []byte(\"a:1\\r\\n-:2\\r\\nb:2\\r\\n1\"), records not found skipped to CRLF",
+			data:            []byte("a:1\r\n-:2\r\nb:2\r\n1"),
+			finder:          g.Rule("field"),
+			sync:            NewCrLfFinder(),
+			expectedRecords: []string{"field:0-5(name:0-1 value:2-3)", "field:10-15(name:10-11 value:12-13)"},
+			expectedErrs:    []string{"abnfp: syntax not found: skipped 5-10", "abnfp: syntax not found: skipped 15-16"},
+		},
+		{
+			testName:        "data: []byte(\"a\\r\\nb:2\\r\\n\"), record not found skipped to CRLF",
+			data:            []byte("a\r\nb:2\r\n"),
+			finder:          g.Rule("field"),
+			sync:            NewCrLfFinder(),
+			expectedRecords: []string{"field:3-8(name:3-4 value:5-6)"},
+			expectedErrs:    []string{"abnfp: syntax not found: skipped 0-3"},
+		},
+		{
+			testName:        "data: []byte(\"ab\"), empty record not found",
+			data:            []byte("ab"),
+			finder:          NewVariableRepetitionFinder(NewDigitFinder()),
+			sync:            NewByteFinder('a'),
+			expectedRecords: []string{},
+			expectedErrs:    []string{"abnfp: syntax not found: skipped 0-1", "abnfp: syntax not found: skipped 1-2"},
+		},
+		{
+			testName:        "data: []byte(\"\"), no records",
+			data:            []byte(""),
+			finder:          g.Rule("field"),
+			sync:            NewCrLfFinder(),
+			expectedRecords: []string{},
+			expectedErrs:    []string{},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			records, errs := ParseRecords(testCase.data, testCase.finder, testCase.sync)
+			actualRecords := []string{}
+			for _, record := range records {
+				actualRecords = append(actualRecords, formatNode(record))
+			}
+			sliceEquals(testCase.testName, t, testCase.expectedRecords, actualRecords)
+			actualErrs := []string{}
+			for _, err := range errs {
+				equals(testCase.testName, t, true, errors.Is(err, ErrNotFound))
+				actualErrs = append(actualErrs, err.Error())
+			}
+			sliceEquals(testCase.testName, t, testCase.expectedErrs, actualErrs)
+		})
+	}
+}
+
+func TestRecordError(t *testing.T) {
+	_, errs := ParseRecords([]byte("-\r\n"), NewAlphaFinder(), NewCrLfFinder())
+	var recordErr *RecordError
+	equals("errors.As", t, true, errors.As(errs[0], &recordErr))
+	equals("Start", t, 0, recordErr.Start)
+	equals("End", t, 3, recordErr.End)
+}
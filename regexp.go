@@ -0,0 +1,266 @@
+package abnfp
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrNotRegular is returned when a Finder can not be converted to a regular
+// expression.
+var ErrNotRegular = errors.New("abnfp: not regular")
+
+// The precedences of the regular expression operators.
+const (
+	regexpPrecedenceAlternation = iota
+	regexpPrecedenceConcatenation
+	regexpPrecedenceRepetition
+	regexpPrecedenceAtom
+)
+
+// The maximum count of a repetition in RE2 syntax.
+const maxRegexpRepeat = 1000
+
+var regexpGroupName = regexp.MustCompile("^[A-Za-z_][A-Za-z0-9_]*$")
+
+// regexpConverter converts Finders to regular expressions.
+// rules are the names of the rules being converted, to detect recursions.
+type regexpConverter struct {
+	rules []string
+}
+
+func formatRegexpByte(b byte) (string, error) {
+	if b >= 0x80 {
+		// NOTE
+		// Go regexp matches UTF-8. It can't match a single byte over 0x7F.
+		return "", fmt.Errorf("%w: byte %%x%02X is over %%x7F", ErrNotRegular, b)
+	}
+	if (b >= '0' && b <= '9') || (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') {
+		return string(b), nil
+	}
+	return fmt.Sprintf("\\x%02X", b), nil
+}
+
+// convertAt converts finder, and encloses it in a group if its precedence is
+// lower than precedence.
+func (c *regexpConverter) convertAt(finder Finder, precedence int) (string, error) {
+	pattern, finderPrecedence, err := c.convert(finder)
+	if err != nil {
+		return "", err
+	}
+	if finderPrecedence < precedence {
+		return "(?:" + pattern + ")", nil
+	}
+	return pattern, nil
+}
+
+func (c *regexpConverter) convert(finder Finder) (pattern string, precedence int, err error) {
+	switch f := finder.(type) {
+	case ByteFinder:
+		return c.convertBytes([]byte{f.target})
+	case *ByteFinder:
+		return c.convertBytes([]byte{f.target})
+	case BytesFinder:
+		if len(f.target) == 0 {
+			break
+		}
+		return c.convertBytes(f.target)
+	case *BytesFinder:
+		if len(f.target) == 0 {
+			break
+		}
+		return c.convertBytes(f.target)
+	case CaseInsensitiveBytesFinder:
+		if len(f.target) == 0 {
+			break
+		}
+		return c.convertCaseInsensitiveBytes(f.target)
+	case *CaseInsensitiveBytesFinder:
+		if len(f.target) == 0 {
+			break
+		}
+		return c.convertCaseInsensitiveBytes(f.target)
+	case CrLfFinder, *CrLfFinder:
+		return c.convertBytes([]byte("\r\n"))
+	case *ValueRangeAlternativesFinder:
+		return c.convertRange(f.rangeStart, f.rangeEnd)
+	case *RuleFinder:
+		for _, rule := range c.rules {
+			if rule == f.name {
+				return "", 0, fmt.Errorf("%w: rule %v is recursive", ErrNotRegular, f.name)
+			}
+		}
+		c.rules = append(c.rules, f.name)
+		defer func() { c.rules = c.rules[:len(c.rules)-1] }()
+		return c.convert(f.newFinder())
+	case *ConcatenationFinder:
+		if len(f.childFinders) == 1 {
+			return c.convert(f.childFinders[0])
+		}
+		patterns := []string{}
+		for _, childFinder := range f.childFinders {
+			childPattern, err := c.convertAt(childFinder, regexpPrecedenceConcatenation)
+			if err != nil {
+				return "", 0, err
+			}
+			patterns = append(patterns, childPattern)
+		}
+		if len(patterns) == 0 {
+			return "(?:)", regexpPrecedenceAtom, nil
+		}
+		return strings.Join(patterns, ""), regexpPrecedenceConcatenation, nil
+	case *AlternativesFinder:
+		return c.convertAlternatives(f.childFinders)
+	case *LongestMatchAlternativesFinder:
+		return c.convertAlternatives(f.childFinders)
+	case *VariableRepetitionMinMaxFinder:
+		return c.convertRepetition(f.min, f.max, f.childFinder, "")
+	case *LazyRepetitionMinMaxFinder:
+		return c.convertRepetition(f.min, f.max, f.childFinder, "?")
+	case *CaptureFinder:
+		childPattern, _, err := c.convert(f.childFinder)
+		if err != nil {
+			return "", 0, err
+		}
+		if !regexpGroupName.MatchString(f.name) {
+			return "(?:" + childPattern + ")", regexpPrecedenceAtom, nil
+		}
+		return "(?P<" + f.name + ">" + childPattern + ")", regexpPrecedenceAtom, nil
+	}
+	// NOTE
+	// RE2 has no atomic groups, so a PossessiveFinder is converted as its
+	// child. The regular expression might match the data which the
+	// PossessiveFinder doesn't find, because it never gives back the data.
+	if child, ok := syntaxChild(finder); ok {
+		return c.convert(child)
+	}
+	text, _ := newPrinter().format(finder)
+	return "", 0, fmt.Errorf("%w: %v can not be converted", ErrNotRegular, text)
+}
+
+func (c *regexpConverter) convertAlternatives(childFinders []Finder) (string, int, error) {
+	if len(childFinders) == 1 {
+		return c.convert(childFinders[0])
+	}
+	if len(childFinders) == 0 {
+		// Nothing matches it.
+		return "[^\\x00-\\x{10FFFF}]", regexpPrecedenceAtom, nil
+	}
+	patterns := []string{}
+	for _, childFinder := range childFinders {
+		childPattern, err := c.convertAt(childFinder, regexpPrecedenceAlternation)
+		if err != nil {
+			return "", 0, err
+		}
+		patterns = append(patterns, childPattern)
+	}
+	return strings.Join(patterns, "|"), regexpPrecedenceAlternation, nil
+}
+
+func (c *regexpConverter) convertBytes(target []byte) (string, int, error) {
+	pattern := ""
+	for _, b := range target {
+		s, err := formatRegexpByte(b)
+		if err != nil {
+			return "", 0, err
+		}
+		pattern += s
+	}
+	if len(target) == 1 {
+		return pattern, regexpPrecedenceAtom, nil
+	}
+	return pattern, regexpPrecedenceConcatenation, nil
+}
+
+// convertCaseInsensitiveBytes converts the letters of target to the classes
+// like [aA].
+//
+// NOTE
+// The flag (?i) is not used, because it also matches the non US-ASCII
+// characters, e.g. (?i)k matches U+212A KELVIN SIGN.
+func (c *regexpConverter) convertCaseInsensitiveBytes(target []byte) (string, int, error) {
+	pattern := ""
+	for _, b := range target {
+		s, err := formatRegexpByte(b)
+		if err != nil {
+			return "", 0, err
+		}
+		if toLowerByte(b) != toUpperByte(b) {
+			s = "[" + string(toLowerByte(b)) + string(toUpperByte(b)) + "]"
+		}
+		pattern += s
+	}
+	if len(target) == 1 {
+		return pattern, regexpPrecedenceAtom, nil
+	}
+	return pattern, regexpPrecedenceConcatenation, nil
+}
+
+func (c *regexpConverter) convertRange(rangeStart byte, rangeEnd byte) (string, int, error) {
+	if rangeStart > rangeEnd {
+		return "[^\\x00-\\x{10FFFF}]", regexpPrecedenceAtom, nil
+	}
+	if rangeStart == rangeEnd {
+		return c.convertBytes([]byte{rangeStart})
+	}
+	start, err := formatRegexpByte(rangeStart)
+	if err != nil {
+		return "", 0, err
+	}
+	end, err := formatRegexpByte(rangeEnd)
+	if err != nil {
+		return "", 0, err
+	}
+	return "[" + start + "-" + end + "]", regexpPrecedenceAtom, nil
+}
+
+// convertRepetition converts the repetition of childFinder.
+// lazy is "?" for the lazy repetition, or "" for the greedy one.
+func (c *regexpConverter) convertRepetition(min int, max int, childFinder Finder, lazy string) (string, int, error) {
+	if min > maxRegexpRepeat || max > maxRegexpRepeat {
+		return "", 0, fmt.Errorf("%w: repetition %v is over %v", ErrNotRegular, formatRepeat(min, max), maxRegexpRepeat)
+	}
+	childPattern, err := c.convertAt(childFinder, regexpPrecedenceAtom)
+	if err != nil {
+		return "", 0, err
+	}
+	switch {
+	case min == 0 && max < 0:
+		return childPattern + "*" + lazy, regexpPrecedenceRepetition, nil
+	case min == 1 && max < 0:
+		return childPattern + "+" + lazy, regexpPrecedenceRepetition, nil
+	case min == 0 && max == 1:
+		return childPattern + "?" + lazy, regexpPrecedenceRepetition, nil
+	case max < 0:
+		return fmt.Sprintf("%v{%v,}%v", childPattern, min, lazy), regexpPrecedenceRepetition, nil
+	case min == max:
+		return fmt.Sprintf("%v{%v}", childPattern, min), regexpPrecedenceRepetition, nil
+	}
+	return fmt.Sprintf("%v{%v,%v}%v", childPattern, min, max, lazy), regexpPrecedenceRepetition, nil
+}
+
+// RegexpString converts finder to the regular expression in RE2 syntax which
+// matches the same language. The CaptureFinders are converted to the named groups.
+// RegexpString returns an error wrapping ErrNotRegular if finder has a recursive
+// rule, a Finder defined outside of this package, or a byte over %x7F.
+func RegexpString(finder Finder) (string, error) {
+	pattern, _, err := (&regexpConverter{rules: []string{}}).convert(finder)
+	return pattern, err
+}
+
+// CompileRegexp returns the regular expression which finds the syntax of finder
+// from the beginning of the data. See RegexpString for the errors.
+//
+// NOTE
+// If the syntax is ambiguous, the regular expression might find shorter data
+// than finder. e.g. *( *"ab" / "a" ) finds "a" from "a", but the regular
+// expression finds the empty data, because it doesn't repeat the empty data.
+// Anchor the end with "$" to check that all of the data is the syntax.
+func CompileRegexp(finder Finder) (*regexp.Regexp, error) {
+	pattern, err := RegexpString(finder)
+	if err != nil {
+		return nil, err
+	}
+	return regexp.Compile("^(?:" + pattern + ")")
+}
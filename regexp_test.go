@@ -0,0 +1,162 @@
+package abnfp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegexpString(t *testing.T) {
+	type TestCase struct {
+		testName        string
+		finder          Finder
+		expectedPattern string
+		expectedErr     string
+	}
+
+	// list = item *( "," item )
+	// item = 1*DIGIT / "(" list ")"
+	var list *RuleFinder
+	item := NewRuleFinder("item", func() Finder {
+		return NewAlternativesFinder([]Finder{
+			NewVariableRepetitionMinFinder(1, NewDigitFinder()),
+			NewConcatenationFinder([]Finder{NewByteFinder('('), list, NewByteFinder(')')}),
+		})
+	})
+	list = NewRuleFinder("list", func() Finder {
+		return NewConcatenationFinder([]Finder{
+			item,
+			NewVariableRepetitionFinder(NewConcatenationFinder([]Finder{NewByteFinder(','), item})),
+		})
+	})
+
+	tests := []TestCase{
+		{
+			testName:        "ByteFinder",
+			finder:          NewByteFinder('.'),
+			expectedPattern: "\\x2E",
+		},
+		{
+			testName:        "CaseInsensitiveBytesFinder",
+			finder:          NewCaseInsensitiveBytesFinder([]byte("Ok.")),
+			expectedPattern: "[oO][kK]\\x2E",
+		},
+		{
+			testName:        "BytesFinder",
+			finder:          NewBytesFinder([]byte("a.b")),
+			expectedPattern: "a\\x2Eb",
+		},
+		{
+			testName:        "CrLfFinder",
+			finder:          NewCrLfFinder(),
+			expectedPattern: "\\x0D\\x0A",
+		},
+		{
+			testName:        "ValueRangeAlternativesFinder",
+			finder:          NewDigitFinder(),
+			expectedPattern: "[0-9]",
+		},
+		{
+			testName:        "AlternativesFinder",
+			finder:          NewHexDigFinder(),
+			expectedPattern: "[0-9]|A|B|C|D|E|F",
+		},
+		{
+			testName: "ConcatenationFinder",
+			finder: NewConcatenationFinder([]Finder{
+				NewAlphaFinder(),
+				NewOptionalSequenceFinder(NewBytesFinder([]byte("ab"))),
+			}),
+			expectedPattern: "(?:[A-Z]|[a-z])(?:ab)?",
+		},
+		{
+			testName: "VariableRepetitionMinMaxFinder",
+			finder: NewConcatenationFinder([]Finder{
+				NewVariableRepetitionFinder(NewByteFinder('a')),
+				NewVariableRepetitionMinFinder(1, NewByteFinder('b')),
+				NewVariableRepetitionMinFinder(2, NewByteFinder('c')),
+				NewVariableRepetitionMinMaxFinder(2, 3, NewByteFinder('d')),
+				NewSpecificRepetitionFinder(2, NewByteFinder('e')),
+				NewVariableRepetitionFinder(NewVariableRepetitionFinder(NewByteFinder('f'))),
+			}),
+			expectedPattern: "a*b+c{2,}d{2,3}e{2}(?:f*)*",
+		},
+		{
+			testName: "PossessiveFinder",
+			finder: NewConcatenationFinder([]Finder{
+				NewPossessiveFinder(NewVariableRepetitionMinFinder(1, NewAlphaFinder())),
+				NewByteFinder('.'),
+			}),
+			expectedPattern: "(?:[A-Z]|[a-z])+\\x2E",
+		},
+		{
+			testName:        "CaptureFinder",
+			finder:          NewCaptureFinder("digits", NewVariableRepetitionFinder(NewDigitFinder())),
+			expectedPattern: "(?P<digits>[0-9]*)",
+		},
+		{
+			testName:        "CaptureFinder with the name which is not a group name",
+			finder:          NewCaptureFinder("digit-list", NewVariableRepetitionFinder(NewDigitFinder())),
+			expectedPattern: "(?:[0-9]*)",
+		},
+		{
+			testName:        "RuleFinder",
+			finder:          NewRuleFinder("digit", func() Finder { return NewDigitFinder() }),
+			expectedPattern: "[0-9]",
+		},
+		{
+			testName:    "recursive RuleFinder",
+			finder:      list,
+			expectedErr: "abnfp: not regular: rule list is recursive",
+		},
+		{
+			testName:    "byte over %x7F",
+			finder:      NewOctetFinder(),
+			expectedErr: "abnfp: not regular: byte %xFF is over %x7F",
+		},
+		{
+			testName:    "repetition over 1000",
+			finder:      NewVariableRepetitionMinFinder(1001, NewDigitFinder()),
+			expectedErr: "abnfp: not regular: repetition 1001* is over 1000",
+		},
+		{
+			testName:    "NotFinder",
+			finder:      NewNotFinder(NewDQuoteFinder()),
+			expectedErr: "abnfp: not regular: <not %x22> can not be converted",
+		},
+		{
+			testName:    "Finder defined outside of this package",
+			finder:      endOfDataFinder{},
+			expectedErr: "abnfp: not regular: <abnfp.endOfDataFinder> can not be converted",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			pattern, err := RegexpString(testCase.finder)
+			if testCase.expectedErr != "" {
+				if err == nil {
+					t.Fatalf("%v: expected err: %v, actual: %v", testCase.testName, testCase.expectedErr, pattern)
+				}
+				equals(testCase.testName, t, true, errors.Is(err, ErrNotRegular))
+				equals(testCase.testName, t, testCase.expectedErr, err.Error())
+				return
+			}
+			equals(testCase.testName, t, nil, err)
+			equals(testCase.testName, t, testCase.expectedPattern, pattern)
+		})
+	}
+}
+
+func TestCompileRegexp(t *testing.T) {
+	re, err := CompileRegexp(NewConcatenationFinder([]Finder{
+		NewCaptureFinder("host", NewVariableRepetitionMinFinder(1, NewAlphaFinder())),
+		NewByteFinder(':'),
+		NewCaptureFinder("port", NewVariableRepetitionFinder(NewDigitFinder())),
+	}))
+	equals("CompileRegexp", t, nil, err)
+	match := re.FindSubmatch([]byte("example:80/index.html"))
+	equals("match", t, "example:80", string(match[0]))
+	equals("host", t, "example", string(match[re.SubexpIndex("host")]))
+	equals("port", t, "80", string(match[re.SubexpIndex("port")]))
+	equals("not at the beginning", t, false, re.MatchString("/example:80"))
+}
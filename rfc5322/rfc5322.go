@@ -0,0 +1,1058 @@
+// Package rfc5322 provides Finders for the Internet Message Format defined in
+// RFC 5322.
+//
+// Only the obsolete syntax needed to read the dates, addresses and comments
+// found in real-world messages is provided (section 4 of RFC 5322).
+//
+// NOTE
+// RFC5322 is highly ambiguous. e.g. "abc" is 1*atext, but it is also three
+// atoms "a", "b" and "c". A space between two words is the trailing CFWS of
+// one atom or the leading CFWS of the next one, it is also both alternatives
+// of FWS, and a phrase is also an obs-phrase. Trying all of them makes the
+// failure of a phrase, a mailbox or a comment exponential in its length.
+// The runs of atext or WSP, FWS, CFWS and phrase never have to be shortened
+// to find a valid message, so they find only the longest data with
+// abnfp.PossessiveFinder.
+package rfc5322
+
+import (
+	abnfp "github.com/um7a/abnf-parser"
+)
+
+// RFC5322 - 3.2.1. Quoted characters
+//
+//  quoted-pair = ("\" (VCHAR / WSP)) / obs-qp
+//
+
+func NewQuotedPairFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			abnfp.NewByteFinder('\\'),
+			abnfp.NewAlternativesFinder([]abnfp.Finder{
+				abnfp.NewVCharFinder(),
+				abnfp.NewWspFinder(),
+			}),
+		}),
+		NewObsQpFinder(),
+	})
+}
+
+// RFC5322 - 3.2.2. Folding White Space and Comments
+//
+//  FWS = ([*WSP CRLF] 1*WSP) / obs-FWS
+//      ; Folding white space
+//
+
+func NewFwsFinder() *abnfp.PossessiveFinder {
+	return abnfp.NewPossessiveFinder(
+		abnfp.NewLongestMatchAlternativesFinder([]abnfp.Finder{
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewOptionalSequenceFinder(
+					abnfp.NewConcatenationFinder([]abnfp.Finder{
+						abnfp.NewPossessiveFinder(abnfp.NewVariableRepetitionFinder(abnfp.NewWspFinder())),
+						abnfp.NewCrLfFinder(),
+					}),
+				),
+				abnfp.NewPossessiveFinder(abnfp.NewVariableRepetitionMinFinder(1, abnfp.NewWspFinder())),
+			}),
+			NewObsFwsFinder(),
+		}),
+	)
+}
+
+// RFC5322 - 3.2.2. Folding White Space and Comments
+//
+//  ctext = %d33-39 /          ; Printable US-ASCII
+//          %d42-91 /          ;  characters not including
+//          %d93-126 /         ;  "(", ")", or "\"
+//          obs-ctext
+//
+
+func NewCTextFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewValueRangeAlternativesFinder(33, 39),
+		abnfp.NewValueRangeAlternativesFinder(42, 91),
+		abnfp.NewValueRangeAlternativesFinder(93, 126),
+		NewObsCTextFinder(),
+	})
+}
+
+// RFC5322 - 3.2.2. Folding White Space and Comments
+//
+//  ccontent = ctext / quoted-pair / comment
+//
+
+func NewCContentFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		NewCTextFinder(),
+		NewQuotedPairFinder(),
+		NewCommentFinder(),
+	})
+}
+
+// RFC5322 - 3.2.2. Folding White Space and Comments
+//
+//  comment = "(" *([FWS] ccontent) [FWS] ")"
+//
+
+func NewCommentFinder() *abnfp.RuleFinder {
+	return abnfp.NewRuleFinder("comment", func() abnfp.Finder {
+		return abnfp.NewConcatenationFinder([]abnfp.Finder{
+			abnfp.NewByteFinder('('),
+			abnfp.NewVariableRepetitionFinder(
+				abnfp.NewConcatenationFinder([]abnfp.Finder{
+					abnfp.NewOptionalSequenceFinder(NewFwsFinder()),
+					NewCContentFinder(),
+				}),
+			),
+			abnfp.NewOptionalSequenceFinder(NewFwsFinder()),
+			abnfp.NewByteFinder(')'),
+		})
+	})
+}
+
+// RFC5322 - 3.2.2. Folding White Space and Comments
+//
+//  CFWS = (1*([FWS] comment) [FWS]) / FWS
+//
+
+func NewCfwsFinder() *abnfp.PossessiveFinder {
+	return abnfp.NewPossessiveFinder(
+		abnfp.NewAlternativesFinder([]abnfp.Finder{
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewVariableRepetitionMinFinder(
+					1,
+					abnfp.NewConcatenationFinder([]abnfp.Finder{
+						abnfp.NewOptionalSequenceFinder(NewFwsFinder()),
+						NewCommentFinder(),
+					}),
+				),
+				abnfp.NewOptionalSequenceFinder(NewFwsFinder()),
+			}),
+			NewFwsFinder(),
+		}),
+	)
+}
+
+// RFC5322 - 3.2.3. Atom
+//
+//  atext = ALPHA / DIGIT /    ; Printable US-ASCII
+//          "!" / "#" /        ;  characters not including
+//          "$" / "%" /        ;  specials.  Used for atoms.
+//          "&" / "'" /
+//          "*" / "+" /
+//          "-" / "/" /
+//          "=" / "?" /
+//          "^" / "_" /
+//          "`" / "{" /
+//          "|" / "}" /
+//          "~"
+//
+
+func NewATextFinder() *abnfp.AlternativesFinder {
+	finders := []abnfp.Finder{
+		abnfp.NewAlphaFinder(),
+		abnfp.NewDigitFinder(),
+	}
+	for _, c := range []byte("!#$%&'*+-/=?^_`{|}~") {
+		finders = append(finders, abnfp.NewByteFinder(c))
+	}
+	return abnfp.NewAlternativesFinder(finders)
+}
+
+// RFC5322 - 3.2.3. Atom
+//
+//  atom = [CFWS] 1*atext [CFWS]
+//
+
+func NewAtomFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+		abnfp.NewPossessiveFinder(abnfp.NewVariableRepetitionMinFinder(1, NewATextFinder())),
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+	})
+}
+
+// RFC5322 - 3.2.3. Atom
+//
+//  dot-atom-text = 1*atext *("." 1*atext)
+//
+
+func NewDotAtomTextFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewPossessiveFinder(abnfp.NewVariableRepetitionMinFinder(1, NewATextFinder())),
+		abnfp.NewVariableRepetitionFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewByteFinder('.'),
+				abnfp.NewPossessiveFinder(abnfp.NewVariableRepetitionMinFinder(1, NewATextFinder())),
+			}),
+		),
+	})
+}
+
+// RFC5322 - 3.2.3. Atom
+//
+//  dot-atom = [CFWS] dot-atom-text [CFWS]
+//
+
+func NewDotAtomFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+		NewDotAtomTextFinder(),
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+	})
+}
+
+// RFC5322 - 3.2.4. Quoted Strings
+//
+//  qtext = %d33 /             ; Printable US-ASCII
+//          %d35-91 /          ;  characters not including
+//          %d93-126 /         ;  "\" or the quote character
+//          obs-qtext
+//
+
+func NewQTextFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewByteFinder(33),
+		abnfp.NewValueRangeAlternativesFinder(35, 91),
+		abnfp.NewValueRangeAlternativesFinder(93, 126),
+		NewObsQTextFinder(),
+	})
+}
+
+// RFC5322 - 3.2.4. Quoted Strings
+//
+//  qcontent = qtext / quoted-pair
+//
+
+func NewQContentFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		NewQTextFinder(),
+		NewQuotedPairFinder(),
+	})
+}
+
+// RFC5322 - 3.2.4. Quoted Strings
+//
+//  quoted-string = [CFWS]
+//                  DQUOTE *([FWS] qcontent) [FWS] DQUOTE
+//                  [CFWS]
+//
+
+func NewQuotedStringFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+		abnfp.NewDQuoteFinder(),
+		abnfp.NewVariableRepetitionFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewOptionalSequenceFinder(NewFwsFinder()),
+				NewQContentFinder(),
+			}),
+		),
+		abnfp.NewOptionalSequenceFinder(NewFwsFinder()),
+		abnfp.NewDQuoteFinder(),
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+	})
+}
+
+// RFC5322 - 3.2.5. Miscellaneous Tokens
+//
+//  word = atom / quoted-string
+//
+
+func NewWordFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		NewAtomFinder(),
+		NewQuotedStringFinder(),
+	})
+}
+
+// RFC5322 - 3.2.5. Miscellaneous Tokens
+//
+//  phrase = 1*word / obs-phrase
+//
+
+func NewPhraseFinder() *abnfp.PossessiveFinder {
+	return abnfp.NewPossessiveFinder(
+		abnfp.NewLongestMatchAlternativesFinder([]abnfp.Finder{
+			abnfp.NewVariableRepetitionMinFinder(1, NewWordFinder()),
+			NewObsPhraseFinder(),
+		}),
+	)
+}
+
+// RFC5322 - 3.2.5. Miscellaneous Tokens
+//
+//  unstructured = (*([FWS] VCHAR) *WSP) / obs-unstruct
+//
+
+func NewUnstructuredFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewVariableRepetitionFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewOptionalSequenceFinder(NewFwsFinder()),
+				abnfp.NewVCharFinder(),
+			}),
+		),
+		abnfp.NewVariableRepetitionFinder(abnfp.NewWspFinder()),
+	})
+}
+
+// RFC5322 - 3.3. Date and Time Specification
+//
+//  date-time = [ day-of-week "," ] date time [CFWS]
+//
+
+func NewDateTimeFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewOptionalSequenceFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				NewDayOfWeekFinder(),
+				abnfp.NewByteFinder(','),
+			}),
+		),
+		NewDateFinder(),
+		NewTimeFinder(),
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+	})
+}
+
+// RFC5322 - 3.3. Date and Time Specification
+//
+//  day-of-week = ([FWS] day-name) / obs-day-of-week
+//
+
+func NewDayOfWeekFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			abnfp.NewOptionalSequenceFinder(NewFwsFinder()),
+			NewDayNameFinder(),
+		}),
+		NewObsDayOfWeekFinder(),
+	})
+}
+
+// RFC5322 - 3.3. Date and Time Specification
+//
+//  day-name = "Mon" / "Tue" / "Wed" / "Thu" /
+//             "Fri" / "Sat" / "Sun"
+//
+
+func NewDayNameFinder() *abnfp.AlternativesFinder {
+	finders := []abnfp.Finder{}
+	for _, name := range []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"} {
+		finders = append(finders, abnfp.NewCaseInsensitiveBytesFinder([]byte(name)))
+	}
+	return abnfp.NewAlternativesFinder(finders)
+}
+
+// RFC5322 - 3.3. Date and Time Specification
+//
+//  date = day month year
+//
+
+func NewDateFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewDayFinder(),
+		NewMonthFinder(),
+		NewYearFinder(),
+	})
+}
+
+// RFC5322 - 3.3. Date and Time Specification
+//
+//  day = ([FWS] 1*2DIGIT FWS) / obs-day
+//
+
+func NewDayFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			abnfp.NewOptionalSequenceFinder(NewFwsFinder()),
+			abnfp.NewVariableRepetitionMinMaxFinder(1, 2, abnfp.NewDigitFinder()),
+			NewFwsFinder(),
+		}),
+		NewObsDayFinder(),
+	})
+}
+
+// RFC5322 - 3.3. Date and Time Specification
+//
+//  month = "Jan" / "Feb" / "Mar" / "Apr" /
+//          "May" / "Jun" / "Jul" / "Aug" /
+//          "Sep" / "Oct" / "Nov" / "Dec"
+//
+
+func NewMonthFinder() *abnfp.AlternativesFinder {
+	finders := []abnfp.Finder{}
+	for _, name := range []string{
+		"Jan", "Feb", "Mar", "Apr", "May", "Jun",
+		"Jul", "Aug", "Sep", "Oct", "Nov", "Dec",
+	} {
+		finders = append(finders, abnfp.NewCaseInsensitiveBytesFinder([]byte(name)))
+	}
+	return abnfp.NewAlternativesFinder(finders)
+}
+
+// RFC5322 - 3.3. Date and Time Specification
+//
+//  year = (FWS 4*DIGIT FWS) / obs-year
+//
+
+func NewYearFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			NewFwsFinder(),
+			abnfp.NewVariableRepetitionMinFinder(4, abnfp.NewDigitFinder()),
+			NewFwsFinder(),
+		}),
+		NewObsYearFinder(),
+	})
+}
+
+// RFC5322 - 3.3. Date and Time Specification
+//
+//  time = time-of-day zone
+//
+
+func NewTimeFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewTimeOfDayFinder(),
+		NewZoneFinder(),
+	})
+}
+
+// RFC5322 - 3.3. Date and Time Specification
+//
+//  time-of-day = hour ":" minute [ ":" second ]
+//
+
+func NewTimeOfDayFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewHourFinder(),
+		abnfp.NewByteFinder(':'),
+		NewMinuteFinder(),
+		abnfp.NewOptionalSequenceFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewByteFinder(':'),
+				NewSecondFinder(),
+			}),
+		),
+	})
+}
+
+// twoDigitFinder returns the finder of 2DIGIT / obs, where obs is
+// [CFWS] 2DIGIT [CFWS].
+func twoDigitFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewSpecificRepetitionFinder(2, abnfp.NewDigitFinder()),
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+			abnfp.NewSpecificRepetitionFinder(2, abnfp.NewDigitFinder()),
+			abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+		}),
+	})
+}
+
+// RFC5322 - 3.3. Date and Time Specification
+//
+//  hour = 2DIGIT / obs-hour
+//
+
+func NewHourFinder() *abnfp.AlternativesFinder {
+	return twoDigitFinder()
+}
+
+// RFC5322 - 3.3. Date and Time Specification
+//
+//  minute = 2DIGIT / obs-minute
+//
+
+func NewMinuteFinder() *abnfp.AlternativesFinder {
+	return twoDigitFinder()
+}
+
+// RFC5322 - 3.3. Date and Time Specification
+//
+//  second = 2DIGIT / obs-second
+//
+
+func NewSecondFinder() *abnfp.AlternativesFinder {
+	return twoDigitFinder()
+}
+
+// RFC5322 - 3.3. Date and Time Specification
+//
+//  zone = (FWS ( "+" / "-" ) 4DIGIT) / obs-zone
+//
+
+func NewZoneFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			NewFwsFinder(),
+			abnfp.NewAlternativesFinder([]abnfp.Finder{
+				abnfp.NewByteFinder('+'),
+				abnfp.NewByteFinder('-'),
+			}),
+			abnfp.NewSpecificRepetitionFinder(4, abnfp.NewDigitFinder()),
+		}),
+		NewObsZoneFinder(),
+	})
+}
+
+// RFC5322 - 3.4. Address Specification
+//
+//  address = mailbox / group
+//
+
+func NewAddressFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		NewMailboxFinder(),
+		NewGroupFinder(),
+	})
+}
+
+// RFC5322 - 3.4. Address Specification
+//
+//  mailbox = name-addr / addr-spec
+//
+
+func NewMailboxFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		NewNameAddrFinder(),
+		NewAddrSpecFinder(),
+	})
+}
+
+// RFC5322 - 3.4. Address Specification
+//
+//  name-addr = [display-name] angle-addr
+//
+
+func NewNameAddrFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewOptionalSequenceFinder(NewDisplayNameFinder()),
+		NewAngleAddrFinder(),
+	})
+}
+
+// RFC5322 - 3.4. Address Specification
+//
+//  angle-addr = [CFWS] "<" addr-spec ">" [CFWS] /
+//               obs-angle-addr
+//
+
+func NewAngleAddrFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+		abnfp.NewByteFinder('<'),
+		NewAddrSpecFinder(),
+		abnfp.NewByteFinder('>'),
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+	})
+}
+
+// RFC5322 - 3.4. Address Specification
+//
+//  group = display-name ":" [group-list] ";" [CFWS]
+//
+
+func NewGroupFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewDisplayNameFinder(),
+		abnfp.NewByteFinder(':'),
+		abnfp.NewOptionalSequenceFinder(NewGroupListFinder()),
+		abnfp.NewByteFinder(';'),
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+	})
+}
+
+// RFC5322 - 3.4. Address Specification
+//
+//  display-name = phrase
+//
+
+func NewDisplayNameFinder() *abnfp.PossessiveFinder {
+	return NewPhraseFinder()
+}
+
+// RFC5322 - 3.4. Address Specification
+//
+//  mailbox-list = (mailbox *("," mailbox)) / obs-mbox-list
+//
+
+func NewMailboxListFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewMailboxFinder(),
+		abnfp.NewVariableRepetitionFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewByteFinder(','),
+				NewMailboxFinder(),
+			}),
+		),
+	})
+}
+
+// RFC5322 - 3.4. Address Specification
+//
+//  address-list = (address *("," address)) / obs-addr-list
+//
+
+func NewAddressListFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewAddressFinder(),
+		abnfp.NewVariableRepetitionFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewByteFinder(','),
+				NewAddressFinder(),
+			}),
+		),
+	})
+}
+
+// RFC5322 - 3.4. Address Specification
+//
+//  group-list = mailbox-list / CFWS / obs-group-list
+//
+
+func NewGroupListFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		NewMailboxListFinder(),
+		NewCfwsFinder(),
+	})
+}
+
+// RFC5322 - 3.4.1. Addr-Spec Specification
+//
+//  addr-spec = local-part "@" domain
+//
+
+func NewAddrSpecFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewLocalPartFinder(),
+		abnfp.NewByteFinder('@'),
+		NewDomainFinder(),
+	})
+}
+
+// RFC5322 - 3.4.1. Addr-Spec Specification
+//
+//  local-part = dot-atom / quoted-string / obs-local-part
+//
+
+func NewLocalPartFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		NewDotAtomFinder(),
+		NewQuotedStringFinder(),
+		NewObsLocalPartFinder(),
+	})
+}
+
+// RFC5322 - 3.4.1. Addr-Spec Specification
+//
+//  domain = dot-atom / domain-literal / obs-domain
+//
+
+func NewDomainFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		NewDotAtomFinder(),
+		NewDomainLiteralFinder(),
+		NewObsDomainFinder(),
+	})
+}
+
+// RFC5322 - 3.4.1. Addr-Spec Specification
+//
+//  domain-literal = [CFWS] "[" *([FWS] dtext) [FWS] "]" [CFWS]
+//
+
+func NewDomainLiteralFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+		abnfp.NewByteFinder('['),
+		abnfp.NewVariableRepetitionFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewOptionalSequenceFinder(NewFwsFinder()),
+				NewDTextFinder(),
+			}),
+		),
+		abnfp.NewOptionalSequenceFinder(NewFwsFinder()),
+		abnfp.NewByteFinder(']'),
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+	})
+}
+
+// RFC5322 - 3.4.1. Addr-Spec Specification
+//
+//  dtext = %d33-90 /          ; Printable US-ASCII
+//          %d94-126 /         ;  characters not including
+//          obs-dtext          ;  "[", "]", or "\"
+//
+
+func NewDTextFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewValueRangeAlternativesFinder(33, 90),
+		abnfp.NewValueRangeAlternativesFinder(94, 126),
+		NewObsDTextFinder(),
+	})
+}
+
+// RFC5322 - 3.6.1. The Origination Date Field
+//
+//  orig-date = "Date:" date-time CRLF
+//
+
+func NewOrigDateFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewCaseInsensitiveBytesFinder([]byte("Date:")),
+		NewDateTimeFinder(),
+		abnfp.NewCrLfFinder(),
+	})
+}
+
+// RFC5322 - 3.6.2. Originator Fields
+//
+//  from = "From:" mailbox-list CRLF
+//
+
+func NewFromFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewCaseInsensitiveBytesFinder([]byte("From:")),
+		NewMailboxListFinder(),
+		abnfp.NewCrLfFinder(),
+	})
+}
+
+// RFC5322 - 3.6.2. Originator Fields
+//
+//  sender = "Sender:" mailbox CRLF
+//
+
+func NewSenderFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewCaseInsensitiveBytesFinder([]byte("Sender:")),
+		NewMailboxFinder(),
+		abnfp.NewCrLfFinder(),
+	})
+}
+
+// RFC5322 - 3.6.2. Originator Fields
+//
+//  reply-to = "Reply-To:" address-list CRLF
+//
+
+func NewReplyToFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewCaseInsensitiveBytesFinder([]byte("Reply-To:")),
+		NewAddressListFinder(),
+		abnfp.NewCrLfFinder(),
+	})
+}
+
+// RFC5322 - 3.6.3. Destination Address Fields
+//
+//  to = "To:" address-list CRLF
+//
+
+func NewToFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewCaseInsensitiveBytesFinder([]byte("To:")),
+		NewAddressListFinder(),
+		abnfp.NewCrLfFinder(),
+	})
+}
+
+// RFC5322 - 3.6.3. Destination Address Fields
+//
+//  cc = "Cc:" address-list CRLF
+//
+
+func NewCcFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewCaseInsensitiveBytesFinder([]byte("Cc:")),
+		NewAddressListFinder(),
+		abnfp.NewCrLfFinder(),
+	})
+}
+
+// RFC5322 - 3.6.4. Identification Fields
+//
+//  message-id = "Message-ID:" msg-id CRLF
+//
+
+func NewMessageIdFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewCaseInsensitiveBytesFinder([]byte("Message-ID:")),
+		NewMsgIdFinder(),
+		abnfp.NewCrLfFinder(),
+	})
+}
+
+// RFC5322 - 3.6.4. Identification Fields
+//
+//  msg-id = [CFWS] "<" id-left "@" id-right ">" [CFWS]
+//
+
+func NewMsgIdFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+		abnfp.NewByteFinder('<'),
+		NewIdLeftFinder(),
+		abnfp.NewByteFinder('@'),
+		NewIdRightFinder(),
+		abnfp.NewByteFinder('>'),
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+	})
+}
+
+// RFC5322 - 3.6.4. Identification Fields
+//
+//  id-left = dot-atom-text / obs-id-left
+//
+
+func NewIdLeftFinder() *abnfp.ConcatenationFinder {
+	return NewDotAtomTextFinder()
+}
+
+// RFC5322 - 3.6.4. Identification Fields
+//
+//  id-right = dot-atom-text / no-fold-literal / obs-id-right
+//
+
+func NewIdRightFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		NewDotAtomTextFinder(),
+		NewNoFoldLiteralFinder(),
+	})
+}
+
+// RFC5322 - 3.6.4. Identification Fields
+//
+//  no-fold-literal = "[" *dtext "]"
+//
+
+func NewNoFoldLiteralFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewByteFinder('['),
+		abnfp.NewVariableRepetitionFinder(NewDTextFinder()),
+		abnfp.NewByteFinder(']'),
+	})
+}
+
+// RFC5322 - 3.6.5. Informational Fields
+//
+//  subject = "Subject:" unstructured CRLF
+//
+
+func NewSubjectFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewCaseInsensitiveBytesFinder([]byte("Subject:")),
+		NewUnstructuredFinder(),
+		abnfp.NewCrLfFinder(),
+	})
+}
+
+// RFC5322 - 4.1. Miscellaneous Obsolete Tokens
+//
+//  obs-NO-WS-CTL = %d1-8 /            ; US-ASCII control
+//                  %d11 /             ;  characters that do not
+//                  %d12 /             ;  include the carriage
+//                  %d14-31 /          ;  return, line feed, and
+//                  %d127              ;  white space characters
+//
+
+func NewObsNoWsCtlFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewValueRangeAlternativesFinder(1, 8),
+		abnfp.NewByteFinder(11),
+		abnfp.NewByteFinder(12),
+		abnfp.NewValueRangeAlternativesFinder(14, 31),
+		abnfp.NewByteFinder(127),
+	})
+}
+
+// RFC5322 - 4.1. Miscellaneous Obsolete Tokens
+//
+//  obs-ctext = obs-NO-WS-CTL
+//
+
+func NewObsCTextFinder() *abnfp.AlternativesFinder {
+	return NewObsNoWsCtlFinder()
+}
+
+// RFC5322 - 4.1. Miscellaneous Obsolete Tokens
+//
+//  obs-qtext = obs-NO-WS-CTL
+//
+
+func NewObsQTextFinder() *abnfp.AlternativesFinder {
+	return NewObsNoWsCtlFinder()
+}
+
+// RFC5322 - 4.1. Miscellaneous Obsolete Tokens
+//
+//  obs-qp = "\" (%d0 / obs-NO-WS-CTL / LF / CR)
+//
+
+func NewObsQpFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewByteFinder('\\'),
+		abnfp.NewAlternativesFinder([]abnfp.Finder{
+			abnfp.NewByteFinder(0),
+			NewObsNoWsCtlFinder(),
+			abnfp.NewLfFinder(),
+			abnfp.NewCrFinder(),
+		}),
+	})
+}
+
+// RFC5322 - 4.1. Miscellaneous Obsolete Tokens
+//
+//  obs-phrase = word *(word / "." / CFWS)
+//
+
+func NewObsPhraseFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewWordFinder(),
+		abnfp.NewVariableRepetitionFinder(
+			abnfp.NewAlternativesFinder([]abnfp.Finder{
+				NewWordFinder(),
+				abnfp.NewByteFinder('.'),
+				NewCfwsFinder(),
+			}),
+		),
+	})
+}
+
+// RFC5322 - 4.2. Obsolete Folding White Space
+//
+//  obs-FWS = 1*WSP *(CRLF 1*WSP)
+//
+
+func NewObsFwsFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewPossessiveFinder(abnfp.NewVariableRepetitionMinFinder(1, abnfp.NewWspFinder())),
+		abnfp.NewVariableRepetitionFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewCrLfFinder(),
+				abnfp.NewPossessiveFinder(abnfp.NewVariableRepetitionMinFinder(1, abnfp.NewWspFinder())),
+			}),
+		),
+	})
+}
+
+// RFC5322 - 4.3. Obsolete Date and Time
+//
+//  obs-day-of-week = [CFWS] day-name [CFWS]
+//
+
+func NewObsDayOfWeekFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+		NewDayNameFinder(),
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+	})
+}
+
+// RFC5322 - 4.3. Obsolete Date and Time
+//
+//  obs-day = [CFWS] 1*2DIGIT [CFWS]
+//
+
+func NewObsDayFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+		abnfp.NewVariableRepetitionMinMaxFinder(1, 2, abnfp.NewDigitFinder()),
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+	})
+}
+
+// RFC5322 - 4.3. Obsolete Date and Time
+//
+//  obs-year = [CFWS] 2*DIGIT [CFWS]
+//
+
+func NewObsYearFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+		abnfp.NewVariableRepetitionMinFinder(2, abnfp.NewDigitFinder()),
+		abnfp.NewOptionalSequenceFinder(NewCfwsFinder()),
+	})
+}
+
+// RFC5322 - 4.3. Obsolete Date and Time
+//
+//  obs-zone = "UT" / "GMT" /     ; Universal Time
+//                                ; North American UT
+//                                ; offsets
+//             "EST" / "EDT" /    ; Eastern:  - 5/ - 4
+//             "CST" / "CDT" /    ; Central:  - 6/ - 5
+//             "MST" / "MDT" /    ; Mountain: - 7/ - 6
+//             "PST" / "PDT" /    ; Pacific:  - 8/ - 7
+//                                ;
+//             %d65-73 /          ; Military zones - "A"
+//             %d75-90 /          ; through "I" and "K"
+//             %d97-105 /         ; through "Z", both
+//             %d107-122          ; upper and lower case
+//
+
+func NewObsZoneFinder() *abnfp.AlternativesFinder {
+	finders := []abnfp.Finder{}
+	for _, name := range []string{
+		"UT", "GMT", "EST", "EDT", "CST", "CDT", "MST", "MDT", "PST", "PDT",
+	} {
+		finders = append(finders, abnfp.NewCaseInsensitiveBytesFinder([]byte(name)))
+	}
+	finders = append(finders,
+		abnfp.NewValueRangeAlternativesFinder(65, 73),
+		abnfp.NewValueRangeAlternativesFinder(75, 90),
+		abnfp.NewValueRangeAlternativesFinder(97, 105),
+		abnfp.NewValueRangeAlternativesFinder(107, 122),
+	)
+	return abnfp.NewAlternativesFinder(finders)
+}
+
+// RFC5322 - 4.4. Obsolete Addressing
+//
+//  obs-local-part = word *("." word)
+//
+
+func NewObsLocalPartFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewWordFinder(),
+		abnfp.NewVariableRepetitionFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewByteFinder('.'),
+				NewWordFinder(),
+			}),
+		),
+	})
+}
+
+// RFC5322 - 4.4. Obsolete Addressing
+//
+//  obs-domain = atom *("." atom)
+//
+
+func NewObsDomainFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewAtomFinder(),
+		abnfp.NewVariableRepetitionFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewByteFinder('.'),
+				NewAtomFinder(),
+			}),
+		),
+	})
+}
+
+// RFC5322 - 4.4. Obsolete Addressing
+//
+//  obs-dtext = obs-NO-WS-CTL / quoted-pair
+//
+
+func NewObsDTextFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		NewObsNoWsCtlFinder(),
+		NewQuotedPairFinder(),
+	})
+}
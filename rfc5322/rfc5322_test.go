@@ -0,0 +1,371 @@
+package rfc5322
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	abnfp "github.com/um7a/abnf-parser"
+)
+
+type TestCase struct {
+	testName      string
+	data          []byte
+	finder        abnfp.Finder
+	expectedFound bool
+	expectedEnd   int
+}
+
+func equals[C comparable](testName string, t *testing.T, expected C, actual C) {
+	if actual != expected {
+		t.Errorf("%v: expected: %v, actual: %v", testName, expected, actual)
+	}
+}
+
+func execFinderTest(tests []TestCase, t *testing.T) {
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			actualFound, actualEnd := testCase.finder.Find(testCase.data)
+			equals(testCase.testName, t, testCase.expectedFound, actualFound)
+			equals(testCase.testName, t, testCase.expectedEnd, actualEnd)
+		})
+	}
+}
+
+func TestFwsFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}, find FWS",
+			data:          []byte{},
+			finder:        NewFwsFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\" \\t a\"), find FWS",
+			data:          []byte(" \t a"),
+			finder:        NewFwsFinder(),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+		{
+			testName:      "data: []byte(\" \\r\\n a\"), find FWS",
+			data:          []byte(" \r\n a"),
+			finder:        NewFwsFinder(),
+			expectedFound: true,
+			expectedEnd:   4,
+		},
+		{
+			testName:      "data: []byte(\"\\r\\na\"), find FWS",
+			data:          []byte("\r\na"),
+			finder:        NewFwsFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestCommentFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"()\"), find comment",
+			data:          []byte("()"),
+			finder:        NewCommentFinder(),
+			expectedFound: true,
+			expectedEnd:   2,
+		},
+		{
+			testName:      "data: []byte(\"(his account)@\"), find comment",
+			data:          []byte("(his account)@"),
+			finder:        NewCommentFinder(),
+			expectedFound: true,
+			expectedEnd:   13,
+		},
+		{
+			testName:      "data: []byte(\"(a (nested (deeply (x))) comment)\"), find comment",
+			data:          []byte("(a (nested (deeply (x))) comment)"),
+			finder:        NewCommentFinder(),
+			expectedFound: true,
+			expectedEnd:   33,
+		},
+		{
+			testName:      "data: []byte(\"(A nice \\\\) chap)\"), find comment",
+			data:          []byte("(A nice \\) chap)"),
+			finder:        NewCommentFinder(),
+			expectedFound: true,
+			expectedEnd:   16,
+		},
+		{
+			testName:      "data: []byte(\"(a (b)\"), find comment",
+			data:          []byte("(a (b)"),
+			finder:        NewCommentFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestCfwsFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\" (c1) \\r\\n (c2) x\"), find CFWS",
+			data:          []byte(" (c1) \r\n (c2) x"),
+			finder:        NewCfwsFinder(),
+			expectedFound: true,
+			expectedEnd:   14,
+		},
+		{
+			testName:      "data: []byte(\"x\"), find CFWS",
+			data:          []byte("x"),
+			finder:        NewCfwsFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestAddrSpecFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"john.doe@example.com\"), find addr-spec",
+			data:          []byte("john.doe@example.com"),
+			finder:        NewAddrSpecFinder(),
+			expectedFound: true,
+			expectedEnd:   20,
+		},
+		{
+			testName:      "data: []byte(\"\\\"john doe\\\"@example.com\"), find addr-spec",
+			data:          []byte("\"john doe\"@example.com"),
+			finder:        NewAddrSpecFinder(),
+			expectedFound: true,
+			expectedEnd:   22,
+		},
+		{
+			testName:      "data: []byte(\"user@[192.168.0.1]\"), find addr-spec",
+			data:          []byte("user@[192.168.0.1]"),
+			finder:        NewAddrSpecFinder(),
+			expectedFound: true,
+			expectedEnd:   18,
+		},
+		{
+			testName:      "data: []byte(\"pete(his account)@silly.test(his host)\"), find addr-spec",
+			data:          []byte("pete(his account)@silly.test(his host)"),
+			finder:        NewAddrSpecFinder(),
+			expectedFound: true,
+			expectedEnd:   38,
+		},
+		{
+			testName:      "data: []byte(\"user@\"), find addr-spec",
+			data:          []byte("user@"),
+			finder:        NewAddrSpecFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"@example.com\"), find addr-spec",
+			data:          []byte("@example.com"),
+			finder:        NewAddrSpecFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestMailboxFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"John Doe <jdoe@machine.example>\"), find mailbox",
+			data:          []byte("John Doe <jdoe@machine.example>"),
+			finder:        NewMailboxFinder(),
+			expectedFound: true,
+			expectedEnd:   31,
+		},
+		{
+			testName:      "data: []byte(\"\\\"Joe Q. Public\\\" <john.q.public@example.com>\"), find mailbox",
+			data:          []byte("\"Joe Q. Public\" <john.q.public@example.com>"),
+			finder:        NewMailboxFinder(),
+			expectedFound: true,
+			expectedEnd:   43,
+		},
+		//
+		// NOTE
+		// In this test case, "1*word" of phrase can not find "John Q." So obs-phrase is tried.
+		//
+		{
+			testName:      "data: []byte(\"John Q. Public <JQB@bar.example>\"), find mailbox",
+			data:          []byte("John Q. Public <JQB@bar.example>"),
+			finder:        NewMailboxFinder(),
+			expectedFound: true,
+			expectedEnd:   32,
+		},
+		{
+			testName:      "data: []byte(\"Pete(A nice \\\\) chap) <pete(his account)@silly.test(his host)>\"), find mailbox",
+			data:          []byte("Pete(A nice \\) chap) <pete(his account)@silly.test(his host)>"),
+			finder:        NewMailboxFinder(),
+			expectedFound: true,
+			expectedEnd:   61,
+		},
+		{
+			testName:      "data: []byte(\"John Doe <jdoe@machine.example\"), find mailbox",
+			data:          []byte("John Doe <jdoe@machine.example"),
+			finder:        NewMailboxFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestDateTimeFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"Fri, 21 Nov 1997 09:55:06 -0600\"), find date-time",
+			data:          []byte("Fri, 21 Nov 1997 09:55:06 -0600"),
+			finder:        NewDateTimeFinder(),
+			expectedFound: true,
+			expectedEnd:   31,
+		},
+		{
+			testName:      "data: []byte(\"1 Jan 2023 00:00 +0000\"), find date-time",
+			data:          []byte("1 Jan 2023 00:00 +0000"),
+			finder:        NewDateTimeFinder(),
+			expectedFound: true,
+			expectedEnd:   22,
+		},
+		//
+		// NOTE
+		// In this test case, obs-year, obs-second and obs-zone are used.
+		//
+		{
+			testName:      "data: []byte(\"21 Nov 97 09:55:06 GMT\"), find date-time",
+			data:          []byte("21 Nov 97 09:55:06 GMT"),
+			finder:        NewDateTimeFinder(),
+			expectedFound: true,
+			expectedEnd:   22,
+		},
+		{
+			testName: "data: folded date-time with comment, find date-time",
+			data: []byte("Thu,\r\n      13\r\n        Feb\r\n          1969\r\n" +
+				"      23:32\r\n               -0330 (Newfoundland Time)"),
+			finder:        NewDateTimeFinder(),
+			expectedFound: true,
+			expectedEnd:   98,
+		},
+		{
+			testName:      "data: []byte(\"Fri, 21 Foo 1997 09:55:06 -0600\"), find date-time",
+			data:          []byte("Fri, 21 Foo 1997 09:55:06 -0600"),
+			finder:        NewDateTimeFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestFieldFinders(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"From: \\\"Mary Smith\\\" <mary@x.test>, jdoe@example.org\\r\\n\"), find from",
+			data:          []byte("From: \"Mary Smith\" <mary@x.test>, jdoe@example.org\r\n"),
+			finder:        NewFromFinder(),
+			expectedFound: true,
+			expectedEnd:   52,
+		},
+		{
+			testName:      "data: []byte(\"To: A Group:Ed Jones <c@a.test>,joe@where.test;\\r\\n\"), find to",
+			data:          []byte("To: A Group:Ed Jones <c@a.test>,joe@where.test;\r\n"),
+			finder:        NewToFinder(),
+			expectedFound: true,
+			expectedEnd:   49,
+		},
+		{
+			testName:      "data: []byte(\"Cc: Undisclosed recipients:;\\r\\n\"), find cc",
+			data:          []byte("Cc: Undisclosed recipients:;\r\n"),
+			finder:        NewCcFinder(),
+			expectedFound: true,
+			expectedEnd:   30,
+		},
+		{
+			testName:      "data: []byte(\"Date: Fri, 21 Nov 1997 09:55:06 -0600\\r\\n\"), find orig-date",
+			data:          []byte("Date: Fri, 21 Nov 1997 09:55:06 -0600\r\n"),
+			finder:        NewOrigDateFinder(),
+			expectedFound: true,
+			expectedEnd:   39,
+		},
+		{
+			testName:      "data: []byte(\"Message-ID: <1234@local.machine.example>\\r\\n\"), find message-id",
+			data:          []byte("Message-ID: <1234@local.machine.example>\r\n"),
+			finder:        NewMessageIdFinder(),
+			expectedFound: true,
+			expectedEnd:   42,
+		},
+		{
+			testName:      "data: []byte(\"Subject: Saying Hello\\r\\n\"), find subject",
+			data:          []byte("Subject: Saying Hello\r\n"),
+			finder:        NewSubjectFinder(),
+			expectedFound: true,
+			expectedEnd:   23,
+		},
+		{
+			testName:      "data: []byte(\"subject: Re: Saying\\r\\n Hello\\r\\n\"), find subject",
+			data:          []byte("subject: Re: Saying\r\n Hello\r\n"),
+			finder:        NewSubjectFinder(),
+			expectedFound: true,
+			expectedEnd:   29,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestAmbiguousDataNotFound(t *testing.T) {
+	type TestCase struct {
+		testName string
+		data     []byte
+		finder   abnfp.Finder
+	}
+
+	tests := []TestCase{
+		{
+			testName: "data: 32 words and \"<bad\", find mailbox",
+			data:     []byte(strings.Repeat("Word ", 32) + "<bad"),
+			finder:   NewMailboxFinder(),
+		},
+		{
+			testName: "data: 32 words and \"<bad\", find address-list",
+			data:     []byte(strings.Repeat("Word ", 32) + "<bad"),
+			finder:   NewAddressListFinder(),
+		},
+		{
+			testName: "data: 32 words with comments and \"<bad\", find mailbox",
+			data:     []byte(strings.Repeat("Word (c) ", 32) + "<bad"),
+			finder:   NewMailboxFinder(),
+		},
+		{
+			testName: "data: 32 obs-phrase words and \"<bad\", find mailbox",
+			data:     []byte(strings.Repeat("Q. ", 32) + "<bad"),
+			finder:   NewMailboxFinder(),
+		},
+		{
+			testName: "data: 32 words and \".\", find group",
+			data:     []byte(strings.Repeat("Word ", 32) + "."),
+			finder:   NewGroupFinder(),
+		},
+		{
+			testName: "data: 32 unclosed comments, find comment",
+			data:     []byte(strings.Repeat("(a ", 32)),
+			finder:   NewCommentFinder(),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			result := abnfp.ParseAt(testCase.data, 0, testCase.finder, abnfp.WithMaxSteps(10000))
+			equals(testCase.testName, t, false, errors.Is(result.Err, abnfp.ErrAborted))
+			equals(testCase.testName, t, true, errors.Is(result.Err, abnfp.ErrNotFound))
+		})
+	}
+}
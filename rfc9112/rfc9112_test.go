@@ -0,0 +1,565 @@
+package rfc9112
+
+import (
+	"testing"
+
+	abnfp "github.com/um7a/abnf-parser"
+)
+
+type TestCase struct {
+	testName      string
+	data          []byte
+	finder        abnfp.Finder
+	expectedFound bool
+	expectedEnd   int
+}
+
+func equals[C comparable](testName string, t *testing.T, expected C, actual C) {
+	if actual != expected {
+		t.Errorf("%v: expected: %v, actual: %v", testName, expected, actual)
+	}
+}
+
+func execFinderTest(tests []TestCase, t *testing.T) {
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			actualFound, actualEnd := testCase.finder.Find(testCase.data)
+			equals(testCase.testName, t, testCase.expectedFound, actualFound)
+			equals(testCase.testName, t, testCase.expectedEnd, actualEnd)
+		})
+	}
+}
+
+func TestTokenFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}, find token",
+			data:          []byte{},
+			finder:        NewTokenFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"GET\"), find token",
+			data:          []byte("GET"),
+			finder:        NewTokenFinder(),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+		{
+			testName:      "data: []byte(\"Content-Type:\"), find token",
+			data:          []byte("Content-Type:"),
+			finder:        NewTokenFinder(),
+			expectedFound: true,
+			expectedEnd:   12,
+		},
+		{
+			testName:      "data: []byte(\"!#$%&'*+-.^_`|~09AZaz\"), find token",
+			data:          []byte("!#$%&'*+-.^_`|~09AZaz"),
+			finder:        NewTokenFinder(),
+			expectedFound: true,
+			expectedEnd:   21,
+		},
+		{
+			testName:      "data: []byte(\"(comment)\"), find token",
+			data:          []byte("(comment)"),
+			finder:        NewTokenFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"a b\"), find token",
+			data:          []byte("a b"),
+			finder:        NewTokenFinder(),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestOwsFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}, find OWS",
+			data:          []byte{},
+			finder:        NewOwsFinder(),
+			expectedFound: true,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\" \\t a\"), find OWS",
+			data:          []byte(" \t a"),
+			finder:        NewOwsFinder(),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+		{
+			testName:      "data: []byte(\"a\"), find RWS",
+			data:          []byte("a"),
+			finder:        NewRwsFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"\\t a\"), find RWS",
+			data:          []byte("\t a"),
+			finder:        NewRwsFinder(),
+			expectedFound: true,
+			expectedEnd:   2,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestObsTextFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{0x7f}, find obs-text",
+			data:          []byte{0x7f},
+			finder:        NewObsTextFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte{0x80}, find obs-text",
+			data:          []byte{0x80},
+			finder:        NewObsTextFinder(),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+		{
+			testName:      "data: []byte{0xff}, find obs-text",
+			data:          []byte{0xff},
+			finder:        NewObsTextFinder(),
+			expectedFound: true,
+			expectedEnd:   1,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestFieldValueFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte{}, find field-value",
+			data:          []byte{},
+			finder:        NewFieldValueFinder(),
+			expectedFound: true,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"text/html; charset=utf-8\\r\\n\"), find field-value",
+			data:          []byte("text/html; charset=utf-8\r\n"),
+			finder:        NewFieldValueFinder(),
+			expectedFound: true,
+			expectedEnd:   24,
+		},
+		{
+			testName:      "data: []byte(\"a b  \\r\\n\"), find field-value",
+			data:          []byte("a b  \r\n"),
+			finder:        NewFieldValueFinder(),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+		{
+			testName:      "data: []byte(\"caf\\xc3\\xa9\\r\\n\"), find field-value",
+			data:          []byte("caf\xc3\xa9\r\n"),
+			finder:        NewFieldValueFinder(),
+			expectedFound: true,
+			expectedEnd:   5,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestQuotedStringFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(`\"\"`), find quoted-string",
+			data:          []byte(`""`),
+			finder:        NewQuotedStringFinder(),
+			expectedFound: true,
+			expectedEnd:   2,
+		},
+		{
+			testName:      "data: []byte(`\"abc\" def`), find quoted-string",
+			data:          []byte(`"abc" def`),
+			finder:        NewQuotedStringFinder(),
+			expectedFound: true,
+			expectedEnd:   5,
+		},
+		{
+			testName:      "data: []byte(`\"a\\\"b\"`), find quoted-string",
+			data:          []byte(`"a\"b"`),
+			finder:        NewQuotedStringFinder(),
+			expectedFound: true,
+			expectedEnd:   6,
+		},
+		{
+			testName:      "data: []byte(`\"abc`), find quoted-string",
+			data:          []byte(`"abc`),
+			finder:        NewQuotedStringFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(`abc\"`), find quoted-string",
+			data:          []byte(`abc"`),
+			finder:        NewQuotedStringFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestHostFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"example.com:80\"), find host",
+			data:          []byte("example.com:80"),
+			finder:        NewHostFinder(),
+			expectedFound: true,
+			expectedEnd:   11,
+		},
+		{
+			testName:      "data: []byte(\"255.255.255.255\"), find IPv4address",
+			data:          []byte("255.255.255.255"),
+			finder:        NewIpv4AddressFinder(),
+			expectedFound: true,
+			expectedEnd:   15,
+		},
+		{
+			testName:      "data: []byte(\"256.1.1.1\"), find IPv4address",
+			data:          []byte("256.1.1.1"),
+			finder:        NewIpv4AddressFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"[2001:db8::1]\"), find host",
+			data:          []byte("[2001:db8::1]"),
+			finder:        NewHostFinder(),
+			expectedFound: true,
+			expectedEnd:   13,
+		},
+		{
+			testName:      "data: []byte(\"[::ffff:192.0.2.1]\"), find host",
+			data:          []byte("[::ffff:192.0.2.1]"),
+			finder:        NewHostFinder(),
+			expectedFound: true,
+			expectedEnd:   18,
+		},
+		{
+			testName:      "data: []byte(\"1:2:3:4:5:6:7:8\"), find IPv6address",
+			data:          []byte("1:2:3:4:5:6:7:8"),
+			finder:        NewIpv6AddressFinder(),
+			expectedFound: true,
+			expectedEnd:   15,
+		},
+		{
+			testName:      "data: []byte(\"user:pass@[::1]:8080/\"), find authority",
+			data:          []byte("user:pass@[::1]:8080/"),
+			finder:        NewAuthorityFinder(),
+			expectedFound: true,
+			expectedEnd:   20,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestRequestLineFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"GET / HTTP/1.1\\r\\n\"), find request-line",
+			data:          []byte("GET / HTTP/1.1\r\n"),
+			finder:        NewRequestLineFinder(),
+			expectedFound: true,
+			expectedEnd:   14,
+		},
+		{
+			testName:      "data: origin-form with query, find request-line",
+			data:          []byte("GET /search?q=abnf%20parser&lang=en HTTP/1.1\r\n"),
+			finder:        NewRequestLineFinder(),
+			expectedFound: true,
+			expectedEnd:   44,
+		},
+		{
+			testName:      "data: absolute-form, find request-line",
+			data:          []byte("GET http://www.example.org/pub/WWW/TheProject.html HTTP/1.1\r\n"),
+			finder:        NewRequestLineFinder(),
+			expectedFound: true,
+			expectedEnd:   59,
+		},
+		{
+			testName:      "data: authority-form, find request-line",
+			data:          []byte("CONNECT www.example.com:80 HTTP/1.1\r\n"),
+			finder:        NewRequestLineFinder(),
+			expectedFound: true,
+			expectedEnd:   35,
+		},
+		{
+			testName:      "data: asterisk-form, find request-line",
+			data:          []byte("OPTIONS * HTTP/1.1\r\n"),
+			finder:        NewRequestLineFinder(),
+			expectedFound: true,
+			expectedEnd:   18,
+		},
+		{
+			testName:      "data: lowercase HTTP-name, find request-line",
+			data:          []byte("GET / http/1.1\r\n"),
+			finder:        NewRequestLineFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: two spaces, find request-line",
+			data:          []byte("GET  / HTTP/1.1\r\n"),
+			finder:        NewRequestLineFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestStatusLineFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"HTTP/1.1 200 OK\\r\\n\"), find status-line",
+			data:          []byte("HTTP/1.1 200 OK\r\n"),
+			finder:        NewStatusLineFinder(),
+			expectedFound: true,
+			expectedEnd:   15,
+		},
+		{
+			testName:      "data: []byte(\"HTTP/1.1 404 Not Found\\r\\n\"), find status-line",
+			data:          []byte("HTTP/1.1 404 Not Found\r\n"),
+			finder:        NewStatusLineFinder(),
+			expectedFound: true,
+			expectedEnd:   22,
+		},
+		{
+			testName:      "data: empty reason-phrase, find status-line",
+			data:          []byte("HTTP/1.1 204 \r\n"),
+			finder:        NewStatusLineFinder(),
+			expectedFound: true,
+			expectedEnd:   13,
+		},
+		{
+			testName:      "data: []byte(\"HTTP/1.1 20 OK\\r\\n\"), find status-line",
+			data:          []byte("HTTP/1.1 20 OK\r\n"),
+			finder:        NewStatusLineFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestFieldLineFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"Host: www.example.com\\r\\n\"), find field-line",
+			data:          []byte("Host: www.example.com\r\n"),
+			finder:        NewFieldLineFinder(),
+			expectedFound: true,
+			expectedEnd:   21,
+		},
+		{
+			testName:      "data: []byte(\"Accept:*/*  \\r\\n\"), find field-line",
+			data:          []byte("Accept:*/*  \r\n"),
+			finder:        NewFieldLineFinder(),
+			expectedFound: true,
+			expectedEnd:   12,
+		},
+		{
+			testName:      "data: []byte(\"Host : a\\r\\n\"), find field-line",
+			data:          []byte("Host : a\r\n"),
+			finder:        NewFieldLineFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: []byte(\"X-Empty:\\r\\n\"), find field-line",
+			data:          []byte("X-Empty:\r\n"),
+			finder:        NewFieldLineFinder(),
+			expectedFound: true,
+			expectedEnd:   8,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestObsFoldFinder(t *testing.T) {
+	tests := []TestCase{
+		{
+			testName:      "data: []byte(\"\\r\\n continued\"), find obs-fold",
+			data:          []byte("\r\n continued"),
+			finder:        NewObsFoldFinder(),
+			expectedFound: true,
+			expectedEnd:   3,
+		},
+		{
+			testName:      "data: []byte(\" \\r\\n\\t\\tcontinued\"), find obs-fold",
+			data:          []byte(" \r\n\t\tcontinued"),
+			finder:        NewObsFoldFinder(),
+			expectedFound: true,
+			expectedEnd:   5,
+		},
+		{
+			testName:      "data: []byte(\"\\r\\nHost: a\"), find obs-fold",
+			data:          []byte("\r\nHost: a"),
+			finder:        NewObsFoldFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+func TestHttpMessageFinder(t *testing.T) {
+	curlRequest := "GET /get?show_env=1 HTTP/1.1\r\n" +
+		"Host: httpbin.org\r\n" +
+		"User-Agent: curl/7.81.0\r\n" +
+		"Accept: */*\r\n" +
+		"\r\n"
+	firefoxRequest := "GET /docs/Web/HTTP HTTP/1.1\r\n" +
+		"Host: developer.mozilla.org\r\n" +
+		"User-Agent: Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/109.0\r\n" +
+		"Accept: text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8\r\n" +
+		"Accept-Language: en-US,en;q=0.5\r\n" +
+		"Accept-Encoding: gzip, deflate, br\r\n" +
+		"Connection: keep-alive\r\n" +
+		"Cookie: _ga=GA1.2.1234567890.1676000000; lux_uid=167600000012345678\r\n" +
+		"Upgrade-Insecure-Requests: 1\r\n" +
+		"If-None-Match: \"a3f5c2b1e\"\r\n" +
+		"\r\n"
+	postRequest := "POST /api/v1/items HTTP/1.1\r\n" +
+		"Host: 192.0.2.10:8080\r\n" +
+		"Content-Type: application/json\r\n" +
+		"Content-Length: 13\r\n" +
+		"\r\n" +
+		"{\"id\": 12345}"
+	nginxResponse := "HTTP/1.1 301 Moved Permanently\r\n" +
+		"Server: nginx/1.18.0 (Ubuntu)\r\n" +
+		"Date: Fri, 10 Feb 2023 12:00:00 GMT\r\n" +
+		"Content-Type: text/html\r\n" +
+		"Content-Length: 178\r\n" +
+		"Connection: keep-alive\r\n" +
+		"Location: https://example.com/\r\n" +
+		"\r\n"
+	obsTextResponse := "HTTP/1.0 200 OK\r\n" +
+		"Content-Disposition: attachment; filename=\"r\xe9sum\xe9.pdf\"\r\n" +
+		"\r\n"
+	noEmptyLine := "GET / HTTP/1.1\r\n" +
+		"Host: example.com\r\n"
+	badFieldLine := "GET / HTTP/1.1\r\n" +
+		"Host example.com\r\n" +
+		"\r\n"
+
+	tests := []TestCase{
+		{
+			testName:      "data: curl request, find HTTP-message",
+			data:          []byte(curlRequest),
+			finder:        NewHttpMessageFinder(),
+			expectedFound: true,
+			expectedEnd:   len(curlRequest),
+		},
+		{
+			testName:      "data: firefox request, find HTTP-message",
+			data:          []byte(firefoxRequest),
+			finder:        NewHttpMessageFinder(),
+			expectedFound: true,
+			expectedEnd:   len(firefoxRequest),
+		},
+		{
+			testName:      "data: request with body, find HTTP-message",
+			data:          []byte(postRequest),
+			finder:        NewHttpMessageFinder(),
+			expectedFound: true,
+			expectedEnd:   len(postRequest),
+		},
+		{
+			testName:      "data: nginx response, find HTTP-message",
+			data:          []byte(nginxResponse),
+			finder:        NewHttpMessageFinder(),
+			expectedFound: true,
+			expectedEnd:   len(nginxResponse),
+		},
+		{
+			testName:      "data: response with obs-text, find HTTP-message",
+			data:          []byte(obsTextResponse),
+			finder:        NewHttpMessageFinder(),
+			expectedFound: true,
+			expectedEnd:   len(obsTextResponse),
+		},
+		{
+			testName:      "data: request without empty line, find HTTP-message",
+			data:          []byte(noEmptyLine),
+			finder:        NewHttpMessageFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+		{
+			testName:      "data: request with malformed field-line, find HTTP-message",
+			data:          []byte(badFieldLine),
+			finder:        NewHttpMessageFinder(),
+			expectedFound: false,
+			expectedEnd:   0,
+		},
+	}
+	execFinderTest(tests, t)
+}
+
+// endOfDataFinder finds the end of the data.
+type endOfDataFinder struct{}
+
+func (finder endOfDataFinder) Find(data []byte) (found bool, end int) {
+	return len(data) == 0, 0
+}
+
+func (finder endOfDataFinder) Copy() abnfp.Finder {
+	return endOfDataFinder{}
+}
+
+func TestGeneratedData(t *testing.T) {
+	tests := []struct {
+		testName string
+		finder   abnfp.Finder
+	}{
+		{testName: "request-line", finder: NewRequestLineFinder()},
+		{testName: "status-line", finder: NewStatusLineFinder()},
+		{testName: "absolute-URI", finder: NewAbsoluteUriFinder()},
+		{testName: "authority", finder: NewAuthorityFinder()},
+		{testName: "HTTP-message", finder: NewHttpMessageFinder()},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			generator := abnfp.NewGenerator(1, 20, 3)
+			finder := abnfp.NewConcatenationFinder([]abnfp.Finder{testCase.finder, endOfDataFinder{}})
+			for i := 0; i < 100; i++ {
+				data, err := generator.Generate(testCase.finder)
+				equals(testCase.testName, t, nil, err)
+				found, _ := finder.Find(data)
+				if !found {
+					t.Errorf("%v: generated data %q is not found", testCase.testName, data)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenRegexp(t *testing.T) {
+	re, err := abnfp.CompileRegexp(NewTokenFinder())
+	equals("CompileRegexp", t, nil, err)
+	for _, data := range []string{"GET", "Content-Type:", "x-forwarded-for ", "!#$%&'*+-.^_`|~", "(", ""} {
+		found, end := NewTokenFinder().Find([]byte(data))
+		loc := re.FindStringIndex(data)
+		equals(data, t, found, loc != nil)
+		if loc != nil {
+			equals(data, t, end, loc[1])
+		}
+	}
+}
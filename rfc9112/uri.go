@@ -0,0 +1,526 @@
+package rfc9112
+
+import (
+	abnfp "github.com/um7a/abnf-parser"
+)
+
+// RFC5234 - B.1. Core Rules
+//
+//  HEXDIG = DIGIT / "A" / "B" / "C" / "D" / "E" / "F"
+//
+// NOTE
+// The quoted strings of ABNF are case-insensitive, and RFC3986 relies on
+// that ("%3a" is a valid pct-encoded). abnfp.NewHexDigFinder only accepts
+// the uppercase letters, so the URI rules use this finder instead.
+
+func newHexDigFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewDigitFinder(),
+		abnfp.NewValueRangeAlternativesFinder('A', 'F'),
+		abnfp.NewValueRangeAlternativesFinder('a', 'f'),
+	})
+}
+
+// RFC3986 - 2.1. Percent-Encoding
+//
+//  pct-encoded = "%" HEXDIG HEXDIG
+//
+
+func NewPctEncodedFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewByteFinder('%'),
+		newHexDigFinder(),
+		newHexDigFinder(),
+	})
+}
+
+// RFC3986 - 2.2. Reserved Characters
+//
+//  sub-delims = "!" / "$" / "&" / "'" / "(" / ")"
+//             / "*" / "+" / "," / ";" / "="
+//
+
+func NewSubDelimsFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewByteFinder('!'),
+		abnfp.NewByteFinder('$'),
+		abnfp.NewByteFinder('&'),
+		abnfp.NewByteFinder('\''),
+		abnfp.NewByteFinder('('),
+		abnfp.NewByteFinder(')'),
+		abnfp.NewByteFinder('*'),
+		abnfp.NewByteFinder('+'),
+		abnfp.NewByteFinder(','),
+		abnfp.NewByteFinder(';'),
+		abnfp.NewByteFinder('='),
+	})
+}
+
+// RFC3986 - 2.3. Unreserved Characters
+//
+//  unreserved = ALPHA / DIGIT / "-" / "." / "_" / "~"
+//
+
+func NewUnreservedFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewAlphaFinder(),
+		abnfp.NewDigitFinder(),
+		abnfp.NewByteFinder('-'),
+		abnfp.NewByteFinder('.'),
+		abnfp.NewByteFinder('_'),
+		abnfp.NewByteFinder('~'),
+	})
+}
+
+// RFC3986 - 3.1. Scheme
+//
+//  scheme = ALPHA *( ALPHA / DIGIT / "+" / "-" / "." )
+//
+
+func NewSchemeFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewAlphaFinder(),
+		abnfp.NewVariableRepetitionFinder(
+			abnfp.NewAlternativesFinder([]abnfp.Finder{
+				abnfp.NewAlphaFinder(),
+				abnfp.NewDigitFinder(),
+				abnfp.NewByteFinder('+'),
+				abnfp.NewByteFinder('-'),
+				abnfp.NewByteFinder('.'),
+			}),
+		),
+	})
+}
+
+// RFC3986 - 3.2.1. User Information
+//
+//  userinfo = *( unreserved / pct-encoded / sub-delims / ":" )
+//
+
+func NewUserInfoFinder() *abnfp.VariableRepetitionMinMaxFinder {
+	return abnfp.NewVariableRepetitionFinder(
+		abnfp.NewAlternativesFinder([]abnfp.Finder{
+			NewUnreservedFinder(),
+			NewPctEncodedFinder(),
+			NewSubDelimsFinder(),
+			abnfp.NewByteFinder(':'),
+		}),
+	)
+}
+
+// RFC3986 - 3.2.2. Host
+//
+//  dec-octet = DIGIT                 ; 0-9
+//            / %x31-39 DIGIT         ; 10-99
+//            / "1" 2DIGIT            ; 100-199
+//            / "2" %x30-34 DIGIT     ; 200-249
+//            / "25" %x30-35          ; 250-255
+//
+// NOTE
+// AlternativesFinder returns the first alternative that matches, so the
+// alternatives are tried from the longest one. Otherwise "255" would be
+// found as "25".
+
+func NewDecOctetFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			abnfp.NewBytesFinder([]byte("25")),
+			abnfp.NewValueRangeAlternativesFinder(0x30, 0x35),
+		}),
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			abnfp.NewByteFinder('2'),
+			abnfp.NewValueRangeAlternativesFinder(0x30, 0x34),
+			abnfp.NewDigitFinder(),
+		}),
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			abnfp.NewByteFinder('1'),
+			abnfp.NewSpecificRepetitionFinder(2, abnfp.NewDigitFinder()),
+		}),
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			abnfp.NewValueRangeAlternativesFinder(0x31, 0x39),
+			abnfp.NewDigitFinder(),
+		}),
+		abnfp.NewDigitFinder(),
+	})
+}
+
+// RFC3986 - 3.2.2. Host
+//
+//  IPv4address = dec-octet "." dec-octet "." dec-octet "." dec-octet
+//
+
+func NewIpv4AddressFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewDecOctetFinder(),
+		abnfp.NewByteFinder('.'),
+		NewDecOctetFinder(),
+		abnfp.NewByteFinder('.'),
+		NewDecOctetFinder(),
+		abnfp.NewByteFinder('.'),
+		NewDecOctetFinder(),
+	})
+}
+
+// RFC3986 - 3.2.2. Host
+//
+//  h16 = 1*4HEXDIG
+//      ; 16 bits of address represented in hexadecimal
+//
+
+func NewH16Finder() *abnfp.VariableRepetitionMinMaxFinder {
+	return abnfp.NewVariableRepetitionMinMaxFinder(1, 4, newHexDigFinder())
+}
+
+// RFC3986 - 3.2.2. Host
+//
+//  ls32 = ( h16 ":" h16 ) / IPv4address
+//       ; least-significant 32 bits of address
+//
+
+func NewLs32Finder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			NewH16Finder(),
+			abnfp.NewByteFinder(':'),
+			NewH16Finder(),
+		}),
+		NewIpv4AddressFinder(),
+	})
+}
+
+// h16Colon returns the finder of n( h16 ":" ).
+func h16Colon(n int) *abnfp.VariableRepetitionMinMaxFinder {
+	return abnfp.NewSpecificRepetitionFinder(
+		n,
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			NewH16Finder(),
+			abnfp.NewByteFinder(':'),
+		}),
+	)
+}
+
+// h16Prefix returns the finder of [ *n( h16 ":" ) h16 ].
+func h16Prefix(n int) *abnfp.VariableRepetitionMinMaxFinder {
+	return abnfp.NewOptionalSequenceFinder(
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			abnfp.NewVariableRepetitionMaxFinder(
+				n,
+				abnfp.NewConcatenationFinder([]abnfp.Finder{
+					NewH16Finder(),
+					abnfp.NewByteFinder(':'),
+				}),
+			),
+			NewH16Finder(),
+		}),
+	)
+}
+
+// RFC3986 - 3.2.2. Host
+//
+//  IPv6address =                            6( h16 ":" ) ls32
+//              /                       "::" 5( h16 ":" ) ls32
+//              / [               h16 ] "::" 4( h16 ":" ) ls32
+//              / [ *1( h16 ":" ) h16 ] "::" 3( h16 ":" ) ls32
+//              / [ *2( h16 ":" ) h16 ] "::" 2( h16 ":" ) ls32
+//              / [ *3( h16 ":" ) h16 ] "::"    h16 ":"   ls32
+//              / [ *4( h16 ":" ) h16 ] "::"              ls32
+//              / [ *5( h16 ":" ) h16 ] "::"              h16
+//              / [ *6( h16 ":" ) h16 ] "::"
+//
+
+func NewIpv6AddressFinder() *abnfp.AlternativesFinder {
+	doubleColon := abnfp.NewBytesFinder([]byte("::"))
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			h16Colon(6), NewLs32Finder(),
+		}),
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			doubleColon, h16Colon(5), NewLs32Finder(),
+		}),
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			abnfp.NewOptionalSequenceFinder(NewH16Finder()), doubleColon, h16Colon(4), NewLs32Finder(),
+		}),
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			h16Prefix(1), doubleColon, h16Colon(3), NewLs32Finder(),
+		}),
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			h16Prefix(2), doubleColon, h16Colon(2), NewLs32Finder(),
+		}),
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			h16Prefix(3), doubleColon, h16Colon(1), NewLs32Finder(),
+		}),
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			h16Prefix(4), doubleColon, NewLs32Finder(),
+		}),
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			h16Prefix(5), doubleColon, NewH16Finder(),
+		}),
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			h16Prefix(6), doubleColon,
+		}),
+	})
+}
+
+// RFC3986 - 3.2.2. Host
+//
+//  IPvFuture = "v" 1*HEXDIG "." 1*( unreserved / sub-delims / ":" )
+//
+
+func NewIpvFutureFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewAlternativesFinder([]abnfp.Finder{
+			abnfp.NewByteFinder('v'),
+			abnfp.NewByteFinder('V'),
+		}),
+		abnfp.NewVariableRepetitionMinFinder(1, newHexDigFinder()),
+		abnfp.NewByteFinder('.'),
+		abnfp.NewVariableRepetitionMinFinder(
+			1,
+			abnfp.NewAlternativesFinder([]abnfp.Finder{
+				NewUnreservedFinder(),
+				NewSubDelimsFinder(),
+				abnfp.NewByteFinder(':'),
+			}),
+		),
+	})
+}
+
+// RFC3986 - 3.2.2. Host
+//
+//  IP-literal = "[" ( IPv6address / IPvFuture  ) "]"
+//
+
+func NewIpLiteralFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewByteFinder('['),
+		abnfp.NewAlternativesFinder([]abnfp.Finder{
+			NewIpv6AddressFinder(),
+			NewIpvFutureFinder(),
+		}),
+		abnfp.NewByteFinder(']'),
+	})
+}
+
+// RFC3986 - 3.2.2. Host
+//
+//  reg-name = *( unreserved / pct-encoded / sub-delims )
+//
+
+func NewRegNameFinder() *abnfp.VariableRepetitionMinMaxFinder {
+	return abnfp.NewVariableRepetitionFinder(
+		abnfp.NewAlternativesFinder([]abnfp.Finder{
+			NewUnreservedFinder(),
+			NewPctEncodedFinder(),
+			NewSubDelimsFinder(),
+		}),
+	)
+}
+
+// RFC3986 - 3.2.2. Host
+//
+//  host = IP-literal / IPv4address / reg-name
+//
+
+func NewHostFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		NewIpLiteralFinder(),
+		NewIpv4AddressFinder(),
+		NewRegNameFinder(),
+	})
+}
+
+// RFC9110 - 4.1. URI References
+//
+//  uri-host = <host, see [URI], Section 3.2.2>
+//
+
+func NewUriHostFinder() *abnfp.AlternativesFinder {
+	return NewHostFinder()
+}
+
+// RFC3986 - 3.2.3. Port
+//
+//  port = *DIGIT
+//
+
+func NewPortFinder() *abnfp.VariableRepetitionMinMaxFinder {
+	return abnfp.NewVariableRepetitionFinder(abnfp.NewDigitFinder())
+}
+
+// RFC3986 - 3.2. Authority
+//
+//  authority = [ userinfo "@" ] host [ ":" port ]
+//
+
+func NewAuthorityFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewOptionalSequenceFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				NewUserInfoFinder(),
+				abnfp.NewByteFinder('@'),
+			}),
+		),
+		NewHostFinder(),
+		abnfp.NewOptionalSequenceFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewByteFinder(':'),
+				NewPortFinder(),
+			}),
+		),
+	})
+}
+
+// RFC3986 - 3.3. Path
+//
+//  pchar = unreserved / pct-encoded / sub-delims / ":" / "@"
+//
+
+func NewPCharFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		NewUnreservedFinder(),
+		NewPctEncodedFinder(),
+		NewSubDelimsFinder(),
+		abnfp.NewByteFinder(':'),
+		abnfp.NewByteFinder('@'),
+	})
+}
+
+// RFC3986 - 3.3. Path
+//
+//  segment = *pchar
+//
+
+func NewSegmentFinder() *abnfp.VariableRepetitionMinMaxFinder {
+	return abnfp.NewVariableRepetitionFinder(NewPCharFinder())
+}
+
+// RFC3986 - 3.3. Path
+//
+//  segment-nz = 1*pchar
+//
+
+func NewSegmentNzFinder() *abnfp.VariableRepetitionMinMaxFinder {
+	return abnfp.NewVariableRepetitionMinFinder(1, NewPCharFinder())
+}
+
+// slashSegments returns the finder of *( "/" segment ).
+func slashSegments() *abnfp.VariableRepetitionMinMaxFinder {
+	return abnfp.NewVariableRepetitionFinder(
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			abnfp.NewByteFinder('/'),
+			NewSegmentFinder(),
+		}),
+	)
+}
+
+// RFC3986 - 3.3. Path
+//
+//  path-abempty = *( "/" segment )
+//
+
+func NewPathAbEmptyFinder() *abnfp.VariableRepetitionMinMaxFinder {
+	return slashSegments()
+}
+
+// RFC3986 - 3.3. Path
+//
+//  path-absolute = "/" [ segment-nz *( "/" segment ) ]
+//
+
+func NewPathAbsoluteFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		abnfp.NewByteFinder('/'),
+		abnfp.NewOptionalSequenceFinder(NewPathRootlessFinder()),
+	})
+}
+
+// RFC3986 - 3.3. Path
+//
+//  path-rootless = segment-nz *( "/" segment )
+//
+
+func NewPathRootlessFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewSegmentNzFinder(),
+		slashSegments(),
+	})
+}
+
+// RFC3986 - 3.3. Path
+//
+//  path-empty = 0<pchar>
+//
+
+func NewPathEmptyFinder() *abnfp.VariableRepetitionMinMaxFinder {
+	return abnfp.NewSpecificRepetitionFinder(0, NewPCharFinder())
+}
+
+// RFC9110 - 4.1. URI References
+//
+//  absolute-path = 1*( "/" segment )
+//
+
+func NewAbsolutePathFinder() *abnfp.VariableRepetitionMinMaxFinder {
+	return abnfp.NewVariableRepetitionMinFinder(
+		1,
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			abnfp.NewByteFinder('/'),
+			NewSegmentFinder(),
+		}),
+	)
+}
+
+// RFC3986 - 3.4. Query
+//
+//  query = *( pchar / "/" / "?" )
+//
+
+func NewQueryFinder() *abnfp.VariableRepetitionMinMaxFinder {
+	return abnfp.NewVariableRepetitionFinder(
+		abnfp.NewAlternativesFinder([]abnfp.Finder{
+			NewPCharFinder(),
+			abnfp.NewByteFinder('/'),
+			abnfp.NewByteFinder('?'),
+		}),
+	)
+}
+
+// RFC3986 - 3. Syntax Components
+//
+//  hier-part = "//" authority path-abempty
+//            / path-absolute
+//            / path-rootless
+//            / path-empty
+//
+
+func NewHierPartFinder() *abnfp.AlternativesFinder {
+	return abnfp.NewAlternativesFinder([]abnfp.Finder{
+		abnfp.NewConcatenationFinder([]abnfp.Finder{
+			abnfp.NewBytesFinder([]byte("//")),
+			NewAuthorityFinder(),
+			NewPathAbEmptyFinder(),
+		}),
+		NewPathAbsoluteFinder(),
+		NewPathRootlessFinder(),
+		NewPathEmptyFinder(),
+	})
+}
+
+// RFC3986 - 4.3. Absolute URI
+//
+//  absolute-URI = scheme ":" hier-part [ "?" query ]
+//
+
+func NewAbsoluteUriFinder() *abnfp.ConcatenationFinder {
+	return abnfp.NewConcatenationFinder([]abnfp.Finder{
+		NewSchemeFinder(),
+		abnfp.NewByteFinder(':'),
+		NewHierPartFinder(),
+		abnfp.NewOptionalSequenceFinder(
+			abnfp.NewConcatenationFinder([]abnfp.Finder{
+				abnfp.NewByteFinder('?'),
+				NewQueryFinder(),
+			}),
+		),
+	})
+}
@@ -0,0 +1,170 @@
+package abnfp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	rfcFooter   = regexp.MustCompile(`\[Page \d+\]\s*$`)
+	rfcHeader   = regexp.MustCompile(`^RFC \d+\s`)
+	rfcRuleLine = regexp.MustCompile(`^( *)([A-Za-z][A-Za-z0-9-]*) *=(/?)`)
+)
+
+// SkippedRuleError is the error of a rule which ExtractABNF skipped, because
+// it is not valid ABNF, like the #list rules of RFC 7230.
+// Err is the error of the compile, which wraps ErrInvalidGrammar. Its line is
+// the line in the rule.
+type SkippedRuleError struct {
+	Name string
+	Err  error
+}
+
+func (e *SkippedRuleError) Error() string {
+	return fmt.Sprintf("abnfp: rule %v is skipped: %v", e.Name, e.Err)
+}
+
+func (e *SkippedRuleError) Unwrap() error {
+	return e.Err
+}
+
+// ExtractABNF extracts the ABNF rules from the plain text of an RFC, like
+// rfc9110.txt.
+// It strips the page headers, footers and form feeds, and it takes the
+// indented blocks which start with "rulename =" or "rulename =/". If the RFC
+// has an appendix of "Collected ABNF", only the rules in it are taken, because
+// they are the same as the rules in the body.
+// The first definition of each rule is taken when the rule is defined twice.
+// The blocks which are not valid ABNF are skipped, and reported as
+// SkippedRuleErrors in skipped.
+func ExtractABNF(text []byte) (abnf []byte, skipped []error) {
+	lines := stripRFCPages(text)
+	if collected := collectedABNFLines(lines); collected != nil {
+		lines = collected
+	}
+	buf := bytes.Buffer{}
+	skipped = []error{}
+	defined := map[string]bool{}
+	for i := 0; i < len(lines); i++ {
+		match := rfcRuleLine.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+		indent := len(match[1])
+		end := i + 1
+		for end < len(lines) && indentOf(lines[end]) > indent {
+			end++
+		}
+		rule := ""
+		for _, line := range lines[i:end] {
+			rule += strings.TrimRight(line[indent:], " ") + "\n"
+		}
+		c := &abnfCompiler{data: []byte(rule)}
+		if _, err := c.compileRuleList(); err != nil {
+			skipped = append(skipped, &SkippedRuleError{Name: match[2], Err: err})
+			continue
+		}
+		name := strings.ToLower(match[2])
+		if defined[name] && match[3] != "/" {
+			continue
+		}
+		defined[name] = true
+		buf.WriteString(rule)
+		i = end - 1
+	}
+	return buf.Bytes(), skipped
+}
+
+// indentOf returns the number of the spaces at the start of line, or -1 if
+// line is blank.
+func indentOf(line string) int {
+	trimmed := strings.TrimLeft(line, " ")
+	if trimmed == "" {
+		return -1
+	}
+	return len(line) - len(trimmed)
+}
+
+// stripRFCPages returns the lines of text without the page breaks.
+// A page ends with a footer like
+//
+//	Fielding, et al.             Standards Track                   [Page 12]
+//
+// and the next page starts with a form feed and a header like
+//
+//	RFC 9110                     HTTP Semantics                    June 2022
+//
+// They and the blank lines around them are stripped, so that a rule
+// continues over the pages.
+func stripRFCPages(text []byte) []string {
+	text = bytes.ReplaceAll(text, []byte("\r\n"), []byte("\n"))
+	lines := []string{}
+	for i, page := range strings.Split(string(text), "\f") {
+		pageLines := strings.Split(page, "\n")
+		pageLines = trimBlankLines(pageLines)
+		if len(pageLines) > 0 && rfcFooter.MatchString(pageLines[len(pageLines)-1]) {
+			pageLines = trimBlankLines(pageLines[:len(pageLines)-1])
+		}
+		if i > 0 && len(pageLines) > 0 && rfcHeader.MatchString(pageLines[0]) {
+			pageLines = trimBlankLines(pageLines[1:])
+		}
+		lines = append(lines, pageLines...)
+	}
+	return lines
+}
+
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && indentOf(lines[0]) < 0 {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && indentOf(lines[len(lines)-1]) < 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// collectedABNFLines returns the lines of the section whose heading contains
+// "Collected ABNF", or nil if there is not the section.
+// The headings are the lines which are not indented.
+func collectedABNFLines(lines []string) []string {
+	for i, line := range lines {
+		if indentOf(line) != 0 || !strings.Contains(line, "Collected ABNF") {
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && indentOf(lines[end]) != 0 {
+			end++
+		}
+		return lines[i+1 : end]
+	}
+	return nil
+}
+
+// CompileRFC compiles the ABNF rules extracted from the plain text of an RFC
+// with ExtractABNF.
+// If ExtractABNF skipped some rules, it returns the Grammar of the other rules
+// with the error which joins the SkippedRuleErrors, so that the caller can
+// tell the Grammar lacks them. Use errors.As to know whether the Grammar is
+// returned.
+func CompileRFC(text []byte) (*Grammar, error) {
+	abnf, skipped := ExtractABNF(text)
+	g, err := CompileGrammar(abnf)
+	if err != nil {
+		return nil, err
+	}
+	return g, errors.Join(skipped...)
+}
+
+// LoadRFC compiles the ABNF rules extracted from the plain text file of an
+// RFC, like rfc9110.txt, as CompileRFC does.
+func LoadRFC(name string) (*Grammar, error) {
+	text, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return CompileRFC(text)
+}
@@ -0,0 +1,114 @@
+package abnfp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExtractABNF(t *testing.T) {
+	type TestCase struct {
+		testName        string
+		text            string
+		expectedABNF    string
+		expectedSkipped []string
+	}
+
+	tests := []TestCase{
+		{
+			testName: "rules in the body",
+			text: "1.  Introduction\r\n" +
+				"\r\n" +
+				"   A message is defined as\r\n" +
+				"\r\n" +
+				"     message = greeting SP name CRLF\r\n" +
+				"     greeting = \"HELLO\"\r\n" +
+				"              / \"HI\" ; comment\r\n" +
+				"\r\n" +
+				"   The list rule is not ABNF.\r\n" +
+				"\r\n" +
+				"     names = #name\r\n" +
+				"\r\n" +
+				"     name = 1*ALPHA\r\n" +
+				"     greeting =/ \"HEY\"\r\n" +
+				"     name = 1*DIGIT\r\n",
+			expectedABNF: "message = greeting SP name CRLF\n" +
+				"greeting = \"HELLO\"\n" +
+				"         / \"HI\" ; comment\n" +
+				"name = 1*ALPHA\n" +
+				"greeting =/ \"HEY\"\n",
+			expectedSkipped: []string{
+				"abnfp: rule names is skipped: abnfp: invalid grammar: line 1, column 9: expected element, found '#'",
+			},
+		},
+		{
+			testName: "rule over pages",
+			text: "   name = 1*( ALPHA\n" +
+				"\n" +
+				"\n" +
+				"Doe                          Informational                     [Page 1]\n" +
+				"\fRFC 9999                     Example Protocol                 October 2026\n" +
+				"\n" +
+				"            / DIGIT )\n",
+			expectedABNF: "name = 1*( ALPHA\n" +
+				"         / DIGIT )\n",
+		},
+		{
+			testName:     "no rules",
+			text:         "Abstract\n\n   This document has no rules.\n",
+			expectedABNF: "",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			abnf, skipped := ExtractABNF([]byte(testCase.text))
+			equals(testCase.testName, t, testCase.expectedABNF, string(abnf))
+			actualSkipped := []string{}
+			for _, err := range skipped {
+				actualSkipped = append(actualSkipped, err.Error())
+				equals(testCase.testName, t, true, errors.Is(err, ErrInvalidGrammar))
+			}
+			equals(testCase.testName, t, len(testCase.expectedSkipped), len(actualSkipped))
+			for i := range testCase.expectedSkipped {
+				if i < len(actualSkipped) {
+					equals(testCase.testName, t, testCase.expectedSkipped[i], actualSkipped[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadRFC(t *testing.T) {
+	g, err := LoadRFC("testdata/rfc/rfc9999.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sliceEquals("RuleNames", t, []string{"greeting", "message", "name", "OWS"}, g.RuleNames())
+	equals("Check", t, nil, g.Check())
+
+	parsed, remaining := Parse([]byte("hello foo, bar\r\n"), g.Rule("message"))
+	equals("parsed", t, "hello foo, bar\r\n", string(parsed))
+	equals("remaining", t, "", string(remaining))
+
+	_, err = LoadRFC("testdata/rfc/not-found.txt")
+	if err == nil {
+		t.Fatal("LoadRFC of not found file returns no error")
+	}
+}
+
+func TestCompileRFCSkippedRule(t *testing.T) {
+	text := "     message = greeting SP names CRLF\n" +
+		"     greeting = \"HELLO\"\n" +
+		"     names = #name\n" +
+		"     name = 1*ALPHA\n"
+	g, err := CompileRFC([]byte(text))
+	if g == nil {
+		t.Fatalf("CompileRFC returns no Grammar: %v", err)
+	}
+	var skipped *SkippedRuleError
+	equals("errors.As", t, true, errors.As(err, &skipped))
+	equals("Name", t, "names", skipped.Name)
+	equals("errors.Is", t, true, errors.Is(err, ErrInvalidGrammar))
+	sliceEquals("RuleNames", t, []string{"message", "greeting", "name"}, g.RuleNames())
+	equals("Check", t, "abnfp: undefined rule: names", g.Check().Error())
+}
@@ -0,0 +1,25 @@
+package abnfp
+
+// Span is the range of the data from the offset Start to End, like the data
+// found by a rule.
+// It doesn't hold the data, so the found data is taken from the data passed
+// to the parse only when it is needed, with Bytes or Text.
+type Span struct {
+	Start int
+	End   int
+}
+
+// Len returns the length of the data in s.
+func (s Span) Len() int {
+	return s.End - s.Start
+}
+
+// Bytes returns the data in s. It is the subslice of data, not a copy.
+func (s Span) Bytes(data []byte) []byte {
+	return data[s.Start:s.End]
+}
+
+// Text returns the data in s as a string.
+func (s Span) Text(data []byte) string {
+	return string(data[s.Start:s.End])
+}
@@ -0,0 +1,75 @@
+package abnfp
+
+import (
+	"testing"
+)
+
+func TestSpan(t *testing.T) {
+	type TestCase struct {
+		testName      string
+		span          Span
+		expectedLen   int
+		expectedBytes []byte
+		expectedText  string
+	}
+
+	data := []byte("a,bc")
+
+	tests := []TestCase{
+		{
+			testName:      "span: 2-4",
+			span:          Span{Start: 2, End: 4},
+			expectedLen:   2,
+			expectedBytes: []byte("bc"),
+			expectedText:  "bc",
+		},
+		{
+			testName:      "span: 1-1",
+			span:          Span{Start: 1, End: 1},
+			expectedLen:   0,
+			expectedBytes: []byte(""),
+			expectedText:  "",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			equals(testCase.testName, t, testCase.expectedLen, testCase.span.Len())
+			sliceEquals(testCase.testName, t, testCase.expectedBytes, testCase.span.Bytes(data))
+			equals(testCase.testName, t, testCase.expectedText, testCase.span.Text(data))
+		})
+	}
+}
+
+// The Spans of the results are the offsets in the data, and Bytes doesn't
+// copy the data.
+func TestSpanOfResults(t *testing.T) {
+	data := []byte("a,bc;")
+	g, err := CompileGrammar([]byte("list = item *( \",\" item )\nitem = 1*ALPHA\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, _, err := ParseTree(data, g.Rule("list"))
+	equals("ParseTree", t, nil, err)
+	equals("ParseTree", t, Span{Start: 2, End: 4}, root.Children[1].Span)
+	equals("ParseTree", t, "bc", root.Children[1].Text(data))
+	equals("ParseTree", t, &data[2], &root.Children[1].Bytes(data)[0])
+
+	captures, _, err := ParseCaptures(data, NewConcatenationFinder([]Finder{
+		NewByteFinder('a'),
+		NewByteFinder(','),
+		NewCaptureFinder("item", g.Rule("item")),
+	}))
+	equals("ParseCaptures", t, nil, err)
+	equals("ParseCaptures", t, Span{Start: 2, End: 4}, captures[0].Span)
+	sliceEquals("ParseCaptures", t, captures[0].Value, captures[0].Bytes(data))
+
+	result := ParseAt(data, 2, g.Rule("item"))
+	equals("ParseAt", t, Span{Start: 2, End: 4}, result.Span)
+	sliceEquals("ParseAt", t, result.Parsed, result.Bytes(data))
+
+	_, errs := ParseRecords(data, g.Rule("list"), NewByteFinder(';'))
+	equals("ParseRecords", t, 1, len(errs))
+	equals("ParseRecords", t, Span{Start: 4, End: 5}, errs[0].(*RecordError).Span)
+}
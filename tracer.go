@@ -0,0 +1,111 @@
+package abnfp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Tracer receives the events of a parse.
+// start and end are the offsets in the data passed to the parse function.
+// Only the rules, that is, RuleFinders, are traced. The other Finders are
+// reported as a part of the innermost rule which has them.
+type Tracer interface {
+	// EnterRule is called when a RuleFinder starts finding its syntax at start.
+	EnterRule(name string, start int)
+	// ExitRule is called when the RuleFinder entered at start finishes
+	// finding its syntax. end is meaningful only if found is true.
+	ExitRule(name string, start int, found bool, end int)
+	// Backtrack is called when a ConcatenationFinder or a repetition at start
+	// can not find its syntax with the first choices of its child Finders,
+	// and starts trying the other choices. name is the innermost rule being
+	// found or recalculated, which has the Finder, or "" if the Finder is not
+	// in a rule.
+	Backtrack(name string, start int)
+	// Recalculate is called when the RuleFinder which found its syntax at
+	// start is asked to find other data.
+	Recalculate(name string, start int, found bool, end int)
+}
+
+// IndentTracer writes the events of a parse to its writer, one event per line.
+// The events of a rule are indented in the rule. e.g.
+//
+//	enter comment at 0
+//	  enter ctext at 1
+//	  exit ctext at 1: found 1-2
+//	exit comment at 0: found 0-3
+//
+// The errors of the writer are ignored.
+type IndentTracer struct {
+	writer io.Writer
+	depth  int
+}
+
+func (tracer *IndentTracer) printf(format string, params ...any) {
+	fmt.Fprintf(tracer.writer, strings.Repeat("  ", tracer.depth)+format+"\n", params...)
+}
+
+func formatResult(found bool, start int, end int) string {
+	if !found {
+		return "not found"
+	}
+	return fmt.Sprintf("found %v-%v", start, end)
+}
+
+func (tracer *IndentTracer) EnterRule(name string, start int) {
+	tracer.printf("enter %v at %v", name, start)
+	tracer.depth++
+}
+
+func (tracer *IndentTracer) ExitRule(name string, start int, found bool, end int) {
+	tracer.depth--
+	tracer.printf("exit %v at %v: %v", name, start, formatResult(found, start, end))
+}
+
+func (tracer *IndentTracer) Backtrack(name string, start int) {
+	if name == "" {
+		tracer.printf("backtrack at %v", start)
+		return
+	}
+	tracer.printf("backtrack in %v at %v", name, start)
+}
+
+func (tracer *IndentTracer) Recalculate(name string, start int, found bool, end int) {
+	tracer.printf("recalculate %v at %v: %v", name, start, formatResult(found, start, end))
+}
+
+func NewIndentTracer(writer io.Writer) *IndentTracer {
+	return &IndentTracer{writer: writer}
+}
+
+// SlogTracer writes the events of a parse to its logger at slog.LevelDebug.
+// Use slog.New to write them to a slog.Handler.
+type SlogTracer struct {
+	logger *slog.Logger
+}
+
+func (tracer *SlogTracer) log(msg string, attrs ...slog.Attr) {
+	tracer.logger.LogAttrs(context.Background(), slog.LevelDebug, msg, attrs...)
+}
+
+func (tracer *SlogTracer) EnterRule(name string, start int) {
+	tracer.log("enter rule", slog.String("rule", name), slog.Int("start", start))
+}
+
+func (tracer *SlogTracer) ExitRule(name string, start int, found bool, end int) {
+	tracer.log("exit rule", slog.String("rule", name), slog.Int("start", start), slog.Bool("found", found), slog.Int("end", end))
+}
+
+func (tracer *SlogTracer) Backtrack(name string, start int) {
+	tracer.log("backtrack", slog.String("rule", name), slog.Int("start", start))
+}
+
+func (tracer *SlogTracer) Recalculate(name string, start int, found bool, end int) {
+	tracer.log("recalculate rule", slog.String("rule", name), slog.Int("start", start), slog.Bool("found", found), slog.Int("end", end))
+}
+
+func NewSlogTracer(logger *slog.Logger) *SlogTracer {
+	return &SlogTracer{logger: logger}
+}
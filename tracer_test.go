@@ -0,0 +1,102 @@
+package abnfp
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+// newTraceTestFinder returns the finder of
+//
+//	pair = *a a
+//	a    = %x61
+func newTraceTestFinder() *RuleFinder {
+	a := NewRuleFinder("a", func() Finder { return NewByteFinder('a') })
+	return NewRuleFinder("pair", func() Finder {
+		return NewConcatenationFinder([]Finder{NewVariableRepetitionFinder(a), a})
+	})
+}
+
+func TestIndentTracer(t *testing.T) {
+	buf := &bytes.Buffer{}
+	parsed, _ := Parse([]byte("aab"), newTraceTestFinder(), WithTracer(NewIndentTracer(buf)))
+	sliceEquals("Parse", t, []byte("aa"), parsed)
+	expected := "enter pair at 0\n" +
+		"  enter a at 0\n" +
+		"  exit a at 0: found 0-1\n" +
+		"  enter a at 1\n" +
+		"  exit a at 1: found 1-2\n" +
+		"  enter a at 2\n" +
+		"  exit a at 2: not found\n" +
+		"  enter a at 2\n" +
+		"  exit a at 2: not found\n" +
+		"  backtrack in pair at 0\n" +
+		"  recalculate a at 1: not found\n" +
+		"  enter a at 1\n" +
+		"  exit a at 1: found 1-2\n" +
+		"exit pair at 0: found 0-2\n"
+	equals("IndentTracer", t, expected, buf.String())
+}
+
+// The backtracks are reported with the innermost rule, or without a rule
+// outside the rules.
+func TestIndentTracerBacktrack(t *testing.T) {
+	buf := &bytes.Buffer{}
+	b := NewRuleFinder("b", func() Finder { return NewByteFinder('b') })
+	finder := NewConcatenationFinder([]Finder{newTraceTestFinder(), NewByteFinder('a'), b})
+	parsed, _ := Parse([]byte("aaab"), finder, WithTracer(NewIndentTracer(buf)))
+	sliceEquals("Parse", t, []byte("aaab"), parsed)
+	expected := "enter pair at 0\n" +
+		"  enter a at 0\n" +
+		"  exit a at 0: found 0-1\n" +
+		"  enter a at 1\n" +
+		"  exit a at 1: found 1-2\n" +
+		"  enter a at 2\n" +
+		"  exit a at 2: found 2-3\n" +
+		"  enter a at 3\n" +
+		"  exit a at 3: not found\n" +
+		"  enter a at 3\n" +
+		"  exit a at 3: not found\n" +
+		"  backtrack in pair at 0\n" +
+		"  recalculate a at 2: not found\n" +
+		"  enter a at 2\n" +
+		"  exit a at 2: found 2-3\n" +
+		"exit pair at 0: found 0-3\n" +
+		"backtrack at 0\n" +
+		"recalculate a at 2: not found\n" +
+		"recalculate a at 1: not found\n" +
+		"enter a at 1\n" +
+		"exit a at 1: found 1-2\n" +
+		"recalculate pair at 0: found 0-2\n" +
+		"enter b at 3\n" +
+		"exit b at 3: found 3-4\n"
+	equals("IndentTracer", t, expected, buf.String())
+}
+
+func TestSlogTracer(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	})
+	finder := NewRuleFinder("a", func() Finder {
+		return NewConcatenationFinder([]Finder{NewOptionalSequenceFinder(NewByteFinder('a')), NewByteFinder('a')})
+	})
+	Parse([]byte("a"), finder, WithTracer(NewSlogTracer(slog.New(handler))))
+	expected := "level=DEBUG msg=\"enter rule\" rule=a start=0\n" +
+		"level=DEBUG msg=backtrack rule=a start=0\n" +
+		"level=DEBUG msg=\"exit rule\" rule=a start=0 found=true end=1\n"
+	equals("SlogTracer", t, expected, buf.String())
+}
+
+func TestParseWithoutTracer(t *testing.T) {
+	finder := newTraceTestFinder()
+	parsed, remaining := Parse([]byte("aab"), finder)
+	sliceEquals("Parse", t, []byte("aa"), parsed)
+	sliceEquals("Parse", t, []byte("b"), remaining)
+}
@@ -0,0 +1,54 @@
+package abnfp
+
+// Node is a rule found by ParseTree.
+// Span is the offsets in the data passed to ParseTree, and Children are the
+// rules found inside the rule in the order of their start.
+type Node struct {
+	Name string
+	Span
+	Children []*Node
+}
+
+// ParseTree finds the syntax from the beginning of data, and returns the tree
+// of the RuleFinders which found the data.
+// If finder is a RuleFinder, the root is the rule. Otherwise, the root is the
+// Node without Name which has the rules inside finder.
+// The rules discarded by backtracking are not included.
+func ParseTree(data []byte, finder Finder, opts ...Option) (root *Node, remaining []byte, err error) {
+	p := newParser(data, opts)
+	finder = p.prepare(finder)
+	found, end, err := p.find(finder, data)
+	if err != nil {
+		return nil, data, err
+	}
+	if !found {
+		return nil, data, notFound(finder, opts)
+	}
+	return newTree(finder, 0, end), data[end:], nil
+}
+
+// newTree returns the tree of the rules in finder, which found the data from
+// start to end.
+func newTree(finder Finder, start int, end int) *Node {
+	root := &Node{Span: Span{Start: start, End: end}}
+	collectNodes(finder, start, end, root)
+	if _, ok := finder.(*RuleFinder); ok {
+		return root.Children[0]
+	}
+	return root
+}
+
+// collectNodes appends the Nodes of the rules in finder to parent.
+// finder found the data from start to end.
+func collectNodes(finder Finder, start int, end int, parent *Node) {
+	if ruleFinder, ok := finder.(*RuleFinder); ok {
+		node := &Node{Name: ruleFinder.name, Span: Span{Start: start, End: end}}
+		parent.Children = append(parent.Children, node)
+		parent = node
+	}
+	if parentFinder, ok := finder.(parentFinder); ok {
+		for _, child := range parentFinder.foundChildren(end - start) {
+			collectNodes(child.finder, start+child.start, start+child.end, parent)
+		}
+	}
+}
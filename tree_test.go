@@ -0,0 +1,97 @@
+package abnfp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// formatNode renders node like "list:0-3(item:0-1 item:2-3)".
+func formatNode(node *Node) string {
+	if node == nil {
+		return "<nil>"
+	}
+	text := fmt.Sprintf("%v:%v-%v", node.Name, node.Start, node.End)
+	if len(node.Children) == 0 {
+		return text
+	}
+	children := []string{}
+	for _, child := range node.Children {
+		children = append(children, formatNode(child))
+	}
+	return text + "(" + strings.Join(children, " ") + ")"
+}
+
+func TestParseTree(t *testing.T) {
+	type TestCase struct {
+		testName          string
+		data              []byte
+		finder            Finder
+		expectedTree      string
+		expectedRemaining []byte
+		expectedErr       error
+	}
+
+	g, err := CompileGrammar([]byte("list = item *( \",\" item )\n" +
+		"item = 1*ALPHA / \"(\" list \")\"\n" +
+		"items = *( item \",\" ) item\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []TestCase{
+		{
+			testName:          "data: []byte(\"a,b;\"), parse list",
+			data:              []byte("a,b;"),
+			finder:            g.Rule("list"),
+			expectedTree:      "list:0-3(item:0-1 item:2-3)",
+			expectedRemaining: []byte(";"),
+		},
+		{
+			testName:          "data: []byte(\"(a),b\"), parse list",
+			data:              []byte("(a),b"),
+			finder:            g.Rule("list"),
+			expectedTree:      "list:0-5(item:0-3(list:1-2(item:1-2)) item:4-5)",
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName:          "data: []byte(\"a,b\"), parse item \",\" item",
+			data:              []byte("a,b"),
+			finder:            NewConcatenationFinder([]Finder{g.Rule("item"), NewByteFinder(','), g.Rule("item")}),
+			expectedTree:      ":0-3(item:0-1 item:2-3)",
+			expectedRemaining: []byte(""),
+		},
+		//
+		// NOTE
+		// In this test case, *( item "," ) finds "a,b," first, then it is recalculated to find "a,".
+		// The item "b" found by *( item "," ) must be discarded.
+		//
+		{
+			testName:          "data: []byte(\"a,b\"), parse items",
+			data:              []byte("a,b"),
+			finder:            g.Rule("items"),
+			expectedTree:      "items:0-3(item:0-1 item:2-3)",
+			expectedRemaining: []byte(""),
+		},
+		{
+			testName:          "data: []byte(\",\"), parse list",
+			data:              []byte(","),
+			finder:            g.Rule("list"),
+			expectedTree:      "<nil>",
+			expectedRemaining: []byte(","),
+			expectedErr:       ErrNotFound,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			root, remaining, err := ParseTree(testCase.data, testCase.finder)
+			equals(testCase.testName, t, testCase.expectedTree, formatNode(root))
+			sliceEquals(testCase.testName, t, testCase.expectedRemaining, remaining)
+			if !errors.Is(err, testCase.expectedErr) {
+				t.Errorf("%v: expected err: %v, actual err: %v", testCase.testName, testCase.expectedErr, err)
+			}
+		})
+	}
+}
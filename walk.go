@@ -0,0 +1,81 @@
+package abnfp
+
+// childFinders returns the child Finders of finder in the order in which they
+// are found, or nil if finder has no children. The definition of a RuleFinder
+// is not its child, because it might refer the rule itself.
+// A new Finder type which has children is added here, so that the walks of
+// the Finders see its children.
+func childFinders(finder Finder) []Finder {
+	switch f := finder.(type) {
+	case *ConcatenationFinder:
+		return f.childFinders
+	case *AlternativesFinder:
+		return f.childFinders
+	case *LongestMatchAlternativesFinder:
+		return f.childFinders
+	case *VariableRepetitionMinMaxFinder:
+		return []Finder{f.childFinder}
+	case *LazyRepetitionMinMaxFinder:
+		return []Finder{f.childFinder}
+	case *ExceptFinder:
+		return []Finder{f.baseFinder, f.excludedFinder}
+	case AndFinder:
+		return []Finder{f.childFinder}
+	case *AndFinder:
+		return []Finder{f.childFinder}
+	case NotFinder:
+		return []Finder{f.childFinder}
+	case *NotFinder:
+		return []Finder{f.childFinder}
+	}
+	if child, ok := syntaxChild(finder); ok {
+		return []Finder{child}
+	}
+	return nil
+}
+
+// syntaxChild returns the child of finder if finder has no syntax of its own,
+// but finds the syntax of the child, like ActionFinder. The diagrams, the
+// generator and the regular expressions see the syntax of the child through
+// it.
+func syntaxChild(finder Finder) (child Finder, ok bool) {
+	switch f := finder.(type) {
+	case *ActionFinder:
+		return f.childFinder, true
+	case *CaptureFinder:
+		return f.childFinder, true
+	case *PossessiveFinder:
+		return f.childFinder, true
+	case *DFAFinder:
+		return f.finder, true
+	}
+	return nil, false
+}
+
+// walkFinders calls visit with finder and all the Finders in it, including the
+// definitions of the rules referred by it.
+// Each rule is walked only once even if it is referred many times.
+func walkFinders(finder Finder, visit func(finder Finder)) {
+	walker := &finderWalker{ruleNames: map[string]bool{}, visit: visit}
+	walker.walk(finder)
+}
+
+type finderWalker struct {
+	ruleNames map[string]bool
+	visit     func(finder Finder)
+}
+
+func (w *finderWalker) walk(finder Finder) {
+	w.visit(finder)
+	if rule, ok := finder.(*RuleFinder); ok {
+		if w.ruleNames[rule.name] {
+			return
+		}
+		w.ruleNames[rule.name] = true
+		w.walk(rule.newFinder())
+		return
+	}
+	for _, childFinder := range childFinders(finder) {
+		w.walk(childFinder)
+	}
+}
@@ -0,0 +1,113 @@
+package abnfp
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChildFinders(t *testing.T) {
+	type TestCase struct {
+		testName         string
+		finder           Finder
+		expectedChildren []string
+	}
+
+	a := NewByteFinder('a')
+	b := NewByteFinder('b')
+	dfaFinder, err := NewDFAFinder(NewConcatenationFinder([]Finder{a, b}), LongestMatch)
+	equals("NewDFAFinder", t, nil, err)
+
+	tests := []TestCase{
+		{testName: "ByteFinder", finder: *a, expectedChildren: []string{}},
+		{testName: "BytesFinder", finder: NewBytesFinder([]byte("ab")), expectedChildren: []string{}},
+		{testName: "CrLfFinder", finder: NewCrLfFinder(), expectedChildren: []string{}},
+		{testName: "CaseInsensitiveBytesFinder", finder: NewCaseInsensitiveBytesFinder([]byte("ab")), expectedChildren: []string{}},
+		{testName: "ValueRangeAlternativesFinder", finder: NewDigitFinder(), expectedChildren: []string{}},
+		{testName: "RuleFinder", finder: NewRuleFinder("a", func() Finder { return a }), expectedChildren: []string{}},
+		{testName: "ProseValFinder", finder: NewProseValFinder("a"), expectedChildren: []string{}},
+		{testName: "FinderFunc", finder: FinderFunc(a.Find), expectedChildren: []string{}},
+		{
+			testName:         "ConcatenationFinder",
+			finder:           NewConcatenationFinder([]Finder{a, b}),
+			expectedChildren: []string{"%x61", "%x62"},
+		},
+		{
+			testName:         "AlternativesFinder",
+			finder:           NewAlternativesFinder([]Finder{a, b}),
+			expectedChildren: []string{"%x61", "%x62"},
+		},
+		{
+			testName:         "LongestMatchAlternativesFinder",
+			finder:           NewLongestMatchAlternativesFinder([]Finder{a, b}),
+			expectedChildren: []string{"%x61", "%x62"},
+		},
+		{
+			testName:         "VariableRepetitionMinMaxFinder",
+			finder:           NewVariableRepetitionFinder(a),
+			expectedChildren: []string{"%x61"},
+		},
+		{
+			testName:         "LazyRepetitionMinMaxFinder",
+			finder:           NewLazyRepetitionMinMaxFinder(0, -1, a),
+			expectedChildren: []string{"%x61"},
+		},
+		{
+			testName:         "ActionFinder",
+			finder:           NewActionFinder(a, func(data []byte, children []any) (any, error) { return nil, nil }),
+			expectedChildren: []string{"%x61"},
+		},
+		{testName: "CaptureFinder", finder: NewCaptureFinder("a", a), expectedChildren: []string{"%x61"}},
+		{testName: "PossessiveFinder", finder: NewPossessiveFinder(a), expectedChildren: []string{"%x61"}},
+		{testName: "AndFinder", finder: NewAndFinder(a), expectedChildren: []string{"%x61"}},
+		{testName: "NotFinder", finder: NewNotFinder(a), expectedChildren: []string{"%x61"}},
+		{testName: "ExceptFinder", finder: NewExceptFinder(a, b), expectedChildren: []string{"%x61", "%x62"}},
+		{testName: "DFAFinder", finder: dfaFinder, expectedChildren: []string{"%x61 %x62"}},
+	}
+
+	tested := map[string]bool{}
+	for _, testCase := range tests {
+		t.Run(testCase.testName, func(t *testing.T) {
+			children := []string{}
+			for _, child := range childFinders(testCase.finder) {
+				children = append(children, fmt.Sprint(child))
+			}
+			sliceEquals(testCase.testName, t, testCase.expectedChildren, children)
+		})
+		finderType := reflect.TypeOf(testCase.finder)
+		if finderType.Kind() == reflect.Pointer {
+			finderType = finderType.Elem()
+		}
+		tested[finderType.Name()] = true
+	}
+
+	// NOTE
+	// All the Finder types of this package must be tested above, so that a
+	// new Finder type with children is added to childFinders.
+	files := token.NewFileSet()
+	packages, err := goparser.ParseDir(files, ".", func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	equals("ParseDir", t, nil, err)
+	for _, file := range packages["abnfp"].Files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || funcDecl.Name.Name != "Find" {
+				continue
+			}
+			receiver := funcDecl.Recv.List[0].Type
+			if star, ok := receiver.(*ast.StarExpr); ok {
+				receiver = star.X
+			}
+			name := receiver.(*ast.Ident).Name
+			if !tested[name] {
+				t.Errorf("Finder type %v is not tested", name)
+			}
+		}
+	}
+}